@@ -0,0 +1,218 @@
+package collada
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+//manifestName is the file at the root of a .zae archive naming its root document.
+const manifestName = "manifest.xml"
+
+//archiveRoot is the name given to the root document of archives written by ExportArchive.
+const archiveRoot = "scene.dae"
+
+//Archive is a COLLADA document loaded from a zipped .zae archive along with the other files it contains.
+type Archive struct {
+	Collada *Collada
+	//Root is the slash separated path of the root document within the archive.
+	Root string
+	//Files holds the contents of the archive, such as the images referenced by the document.
+	Files fs.FS
+}
+
+//manifest is the content of manifest.xml.
+type manifest struct {
+	XMLName xml.Name `xml:"dae_root"`
+	Root    string   `xml:",chardata"`
+}
+
+//LoadArchive reads a .zae archive into memory and loads its root document.
+func LoadArchive(filename string) (*Archive, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return LoadArchiveFromReader(bytes.NewReader(data), int64(len(data)))
+}
+
+//LoadArchiveFromReader loads the root document of a .zae archive, as named by its manifest.xml,
+//or else the only .dae file at the root of the archive.
+func LoadArchiveFromReader(reader io.ReaderAt, size int64) (*Archive, error) {
+	files, err := zip.NewReader(reader, size)
+	if err != nil {
+		return nil, err
+	}
+	root, err := archiveRootPath(files)
+	if err != nil {
+		return nil, err
+	}
+	file, err := files.Open(root)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	collada, err := LoadDocumentFromReader(file)
+	if err != nil {
+		return nil, err
+	}
+	return &Archive{Collada: collada, Root: root, Files: files}, nil
+}
+
+//archiveRootPath finds the root document of an archive.
+func archiveRootPath(files *zip.Reader) (string, error) {
+	data, err := fs.ReadFile(files, manifestName)
+	if err == nil {
+		m := &manifest{}
+		if err := xml.Unmarshal(data, m); err != nil {
+			return "", err
+		}
+		//The root is a uri which may select a scene with a fragment, which is not part of the path.
+		root := strings.TrimSpace(m.Root)
+		if i := strings.IndexByte(root, '#'); i >= 0 {
+			root = root[:i]
+		}
+		if unescaped, err := url.PathUnescape(root); err == nil {
+			root = unescaped
+		}
+		root = path.Clean(strings.TrimPrefix(root, "/"))
+		if !fs.ValidPath(root) {
+			return "", fmt.Errorf("collada: invalid archive root %q", m.Root)
+		}
+		return root, nil
+	}
+	roots := []string{}
+	for _, file := range files.File {
+		if !strings.Contains(file.Name, "/") && strings.EqualFold(path.Ext(file.Name), ".dae") {
+			roots = append(roots, file.Name)
+		}
+	}
+	if len(roots) != 1 {
+		return "", fmt.Errorf("collada: archive has no manifest and %d root documents", len(roots))
+	}
+	return roots[0], nil
+}
+
+//ImagePath returns the path within the archive of the file initializing an image.
+//References are relative to the root document; references outside the archive are an error.
+func (archive *Archive) ImagePath(image *Image) (string, error) {
+	init := image.Source()
+	if init == nil || init.Uri() == "" {
+		return "", fmt.Errorf("collada: image %q does not reference a file", image.Id)
+	}
+	ref, err := url.Parse(strings.Replace(string(init.Uri()), "\\", "/", -1))
+	if err != nil {
+		return "", err
+	}
+	if ref.Scheme != "" || ref.Host != "" || path.IsAbs(ref.Path) {
+		return "", fmt.Errorf("collada: %q is not within the archive", init.Uri())
+	}
+	name := path.Join(path.Dir(archive.Root), ref.Path)
+	if !fs.ValidPath(name) {
+		return "", fmt.Errorf("collada: %q is not within the archive", init.Uri())
+	}
+	return name, nil
+}
+
+//ImageData returns the contents of an image, either embedded in the document or read from the archive.
+func (archive *Archive) ImageData(image *Image) ([]byte, error) {
+	if data, _, ok := image.Embedded(); ok {
+		return data, nil
+	}
+	name, err := archive.ImagePath(image)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadFile(archive.Files, name)
+}
+
+//Export writes the archive to filename.
+func (archive *Archive) Export(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return archive.ExportToWriter(file)
+}
+
+//ExportToWriter writes the archive with its document re-encoded, copying every other file unchanged.
+func (archive *Archive) ExportToWriter(writer io.Writer) error {
+	w := zip.NewWriter(writer)
+	if err := writeArchiveDocument(w, archive.Collada, archive.Root); err != nil {
+		return err
+	}
+	if archive.Files != nil {
+		err := fs.WalkDir(archive.Files, ".", func(name string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || name == manifestName || name == archive.Root {
+				return err
+			}
+			data, err := fs.ReadFile(archive.Files, name)
+			if err != nil {
+				return err
+			}
+			out, err := w.Create(name)
+			if err != nil {
+				return err
+			}
+			_, err = out.Write(data)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+//ExportArchive writes the document to filename as a .zae archive, packaging the images it references
+//as PackageImagesToZip does with location the path the document was loaded from.
+//The references of the document are rewritten to the packaged images.
+func (collada *Collada) ExportArchive(filename, location string) (*PackageReport, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return collada.ExportArchiveToWriter(file, location)
+}
+
+//ExportArchiveToWriter writes the document as a .zae archive with its images in a textures directory.
+func (collada *Collada) ExportArchiveToWriter(writer io.Writer, location string) (*PackageReport, error) {
+	w := zip.NewWriter(writer)
+	report, err := collada.PackageImagesToZip(location, w, "textures")
+	if err != nil {
+		return report, err
+	}
+	if err := writeArchiveDocument(w, collada, archiveRoot); err != nil {
+		return report, err
+	}
+	return report, w.Close()
+}
+
+//writeArchiveDocument writes the manifest of an archive and its root document.
+func writeArchiveDocument(w *zip.Writer, collada *Collada, root string) error {
+	out, err := w.Create(manifestName)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(out)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(&manifest{Root: "./" + root}); err != nil {
+		return err
+	}
+	if out, err = w.Create(root); err != nil {
+		return err
+	}
+	return collada.ExportToWriter(out)
+}
@@ -0,0 +1,83 @@
+package collada
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArchive(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "wood.png"), []byte("wood"), 0644)
+	collada, err := LoadDocument("cube.dae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collada.LibraryImages = []*LibraryImages{{Image: []*Image{
+		{HasId: HasId{"wood"}, InitFrom: &InitFrom{Ref: "wood.png"}},
+	}}}
+	buffer := &bytes.Buffer{}
+	report, err := collada.ExportArchiveToWriter(buffer, filepath.Join(dir, "cube.dae"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Packaged) != 1 || len(report.Missing) != 0 {
+		t.Fatal("wrong report", report)
+	}
+	archive, err := LoadArchiveFromReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archive.Root != "scene.dae" || archive.Collada.FindGeometry("Cube-mesh") == nil {
+		t.Fatal("wrong root document", archive.Root)
+	}
+	image := archive.Collada.FindImage("wood")
+	if data, err := archive.ImageData(image); err != nil || string(data) != "wood" {
+		t.Error("wrong image data", string(data), err)
+	}
+	if data, err := fs.ReadFile(archive.Files, "textures/wood.png"); err != nil || string(data) != "wood" {
+		t.Error("texture not exposed", err)
+	}
+
+	image.Name = "Wood"
+	rewritten := &bytes.Buffer{}
+	if err := archive.ExportToWriter(rewritten); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadArchiveFromReader(bytes.NewReader(rewritten.Bytes()), int64(rewritten.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Collada.FindImage("wood").Name != "Wood" {
+		t.Error("document changes not written")
+	}
+	if _, err := reloaded.ImageData(reloaded.Collada.FindImage("wood")); err != nil {
+		t.Error("texture not copied", err)
+	}
+}
+
+func TestArchiveManifest(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	w := zip.NewWriter(buffer)
+	manifest, _ := w.Create("manifest.xml")
+	manifest.Write([]byte(`<?xml version="1.0"?><dae_root>./models/my%20cube.dae#Scene</dae_root>`))
+	document, _ := w.Create("models/my cube.dae")
+	cube, _ := os.ReadFile("cube.dae")
+	document.Write(cube)
+	w.Close()
+	archive, err := LoadArchiveFromReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archive.Root != "models/my cube.dae" {
+		t.Error("wrong root", archive.Root)
+	}
+	archive.Collada.LibraryImages = []*LibraryImages{{Image: []*Image{{InitFrom: &InitFrom{Ref: "../../outside.png"}}}}}
+	if _, err := archive.ImagePath(archive.Collada.LibraryImages[0].Image[0]); err == nil || !strings.Contains(err.Error(), "not within") {
+		t.Error("expected reference outside the archive to fail", err)
+	}
+}
@@ -0,0 +1,205 @@
+package collada
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+//Floating is the location of times written without a time zone, which are local to an unspecified place.
+//Such times are parsed in Floating and written back without a zone offset.
+var Floating = time.FixedZone("", 0)
+
+//ParseDateTime parses an xs:dateTime, in Floating if it has no time zone.
+func ParseDateTime(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if t, err := time.Parse(time.RFC3339Nano, text); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05", text, Floating)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("collada: invalid date time %q", text)
+	}
+	return t, nil
+}
+
+//FormatDateTime formats a time as an xs:dateTime, omitting the zone of times in Floating.
+func FormatDateTime(t time.Time) string {
+	if t.Location() == Floating {
+		return t.Format("2006-01-02T15:04:05.999999999")
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+//assetXml is the serialized form of an Asset, holding its times as text.
+type assetXml struct {
+	Contributor []*Contributor `xml:"contributor"`
+	Coverage    *Coverage      `xml:"coverage"`
+	Created     string         `xml:"created,omitempty"`
+	Keywords    string         `xml:"keywords,omitempty"`
+	Modified    string         `xml:"modified,omitempty"`
+	Revision    string         `xml:"revision,omitempty"`
+	Subject     string         `xml:"subject,omitempty"`
+	Title       string         `xml:"title,omitempty"`
+	Unit        *Unit          `xml:"unit"`
+	UpAxis      UpAxis         `xml:"up_axis,omitempty"`
+}
+
+func (asset *Asset) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	raw := &assetXml{}
+	if err := d.DecodeElement(raw, &start); err != nil {
+		return err
+	}
+	*asset = Asset{
+		Contributor: raw.Contributor,
+		Coverage:    raw.Coverage,
+		Keywords:    raw.Keywords,
+		Revision:    raw.Revision,
+		Subject:     raw.Subject,
+		Title:       raw.Title,
+		Unit:        raw.Unit,
+		UpAxis:      raw.UpAxis,
+	}
+	asset.Created, asset.createdText = parseAssetTime(raw.Created)
+	asset.Modified, asset.modifiedText = parseAssetTime(raw.Modified)
+	return nil
+}
+
+//parseAssetTime parses the text of an asset time, returning the text itself if it does not parse.
+func parseAssetTime(text string) (time.Time, string) {
+	if text == "" {
+		return time.Time{}, ""
+	}
+	t, err := ParseDateTime(text)
+	if err != nil {
+		return time.Time{}, text
+	}
+	return t, ""
+}
+
+//formatAssetTime formats an asset time, or the text it was loaded from if it did not parse.
+//Times which are neither set nor loaded are left out.
+func formatAssetTime(t time.Time, text string) string {
+	if t.IsZero() {
+		return text
+	}
+	return FormatDateTime(t)
+}
+
+//MarshalXML writes the asset. Only the created and modified times which are set are written.
+func (asset *Asset) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return asset.marshalXML(e, start, e.EncodeElement)
+}
+
+//marshalXML encodes the children through encode.
+func (asset *Asset) marshalXML(e *xml.Encoder, start xml.StartElement, encode func(v interface{}, start xml.StartElement) error) error {
+	raw := &assetXml{
+		Contributor: asset.Contributor,
+		Coverage:    asset.Coverage,
+		Keywords:    asset.Keywords,
+		Revision:    asset.Revision,
+		Subject:     asset.Subject,
+		Title:       asset.Title,
+		Unit:        asset.Unit,
+		UpAxis:      asset.UpAxis,
+	}
+	raw.Created = formatAssetTime(asset.Created, asset.createdText)
+	raw.Modified = formatAssetTime(asset.Modified, asset.modifiedText)
+	return encode(raw, start)
+}
+
+//EffectiveAsset returns the asset in effect for an element of the document, given as a pointer to it.
+//Each field which the element's own asset leaves unset is inherited from the nearest enclosing element
+//whose asset sets it, so a nested <unit> or <up_axis> overrides those of the document.
+//Units default to meters and the up axis to Y_UP. It returns false if the element is not part of the document.
+func (collada *Collada) EffectiveAsset(element interface{}) (*Asset, bool) {
+	target := reflect.ValueOf(element)
+	if target.Kind() != reflect.Ptr || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+	chain, ok := findAssets(reflect.ValueOf(collada).Elem(), target, nil)
+	if !ok {
+		return nil, false
+	}
+	effective := &Asset{Unit: &Unit{HasName: HasName{"meter"}, Meter: 1}, UpAxis: Yup}
+	for _, asset := range chain {
+		effective.inherit(asset)
+	}
+	return effective, true
+}
+
+//inherit overrides the fields of an asset with those set by a nested asset.
+func (asset *Asset) inherit(nested *Asset) {
+	if len(nested.Contributor) > 0 {
+		asset.Contributor = nested.Contributor
+	}
+	if nested.Coverage != nil {
+		asset.Coverage = nested.Coverage
+	}
+	if !nested.Created.IsZero() || nested.createdText != "" {
+		asset.Created, asset.createdText = nested.Created, nested.createdText
+	}
+	if nested.Keywords != "" {
+		asset.Keywords = nested.Keywords
+	}
+	if !nested.Modified.IsZero() || nested.modifiedText != "" {
+		asset.Modified, asset.modifiedText = nested.Modified, nested.modifiedText
+	}
+	if nested.Revision != "" {
+		asset.Revision = nested.Revision
+	}
+	if nested.Subject != "" {
+		asset.Subject = nested.Subject
+	}
+	if nested.Title != "" {
+		asset.Title = nested.Title
+	}
+	if nested.Unit != nil && nested.Unit.Meter > 0 {
+		asset.Unit = nested.Unit
+	}
+	if nested.UpAxis != "" {
+		asset.UpAxis = nested.UpAxis
+	}
+}
+
+var assetType = reflect.TypeOf((*Asset)(nil))
+
+//findAssets searches an addressable struct for the target element, returning the assets of the
+//elements enclosing it from the outermost, including the asset of the target itself.
+func findAssets(v reflect.Value, target reflect.Value, chain []*Asset) ([]*Asset, bool) {
+	if asset := v.FieldByName("Asset"); asset.IsValid() && asset.Type() == assetType && !asset.IsNil() {
+		chain = append(chain[:len(chain):len(chain)], asset.Interface().(*Asset))
+	}
+	if v.Addr().Pointer() == target.Pointer() && v.Type() == target.Elem().Type() {
+		return chain, true
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Struct:
+			if found, ok := findAssets(field, target, chain); ok {
+				return found, true
+			}
+		case reflect.Ptr:
+			if field.Type() != assetType && !field.IsNil() && field.Elem().Kind() == reflect.Struct {
+				if found, ok := findAssets(field.Elem(), target, chain); ok {
+					return found, true
+				}
+			}
+		case reflect.Slice:
+			if field.Type().Elem().Kind() != reflect.Ptr || field.Type().Elem().Elem().Kind() != reflect.Struct {
+				continue
+			}
+			for j := 0; j < field.Len(); j++ {
+				if item := field.Index(j); !item.IsNil() {
+					if found, ok := findAssets(item.Elem(), target, chain); ok {
+						return found, true
+					}
+				}
+			}
+		}
+	}
+	return nil, false
+}
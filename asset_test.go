@@ -0,0 +1,78 @@
+package collada
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDateTime(t *testing.T) {
+	for _, text := range []string{"2013-09-18T14:30:05", "2013-09-18T14:30:05.25", "2013-09-18T14:30:05Z", "2013-09-18T14:30:05+10:00"} {
+		parsed, err := ParseDateTime(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if formatted := FormatDateTime(parsed); formatted != text {
+			t.Error("wrong format", formatted, text)
+		}
+	}
+	if _, err := ParseDateTime("yesterday"); err == nil {
+		t.Error("expected invalid date time error")
+	}
+}
+
+func TestEffectiveAsset(t *testing.T) {
+	collada, err := LoadDocument("cube.dae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2013, 9, 18, 14, 30, 5, 0, Floating)
+	if !collada.Asset.Created.Equal(created) || collada.Asset.Created.Location() != Floating {
+		t.Error("wrong created time", collada.Asset.Created)
+	}
+	node := collada.FindNode("Cube")
+	node.Asset = &Asset{Unit: &Unit{HasName: HasName{"centimeter"}, Meter: 0.01}, Title: "Cube"}
+	mesh := collada.FindGeometry("Cube-mesh").Mesh
+	asset, ok := collada.EffectiveAsset(mesh)
+	if !ok || asset.UpAxis != Zup || asset.Unit.Meter != 1 || !asset.Created.Equal(created) {
+		t.Error("wrong mesh asset", asset)
+	}
+	asset, ok = collada.EffectiveAsset(node)
+	if !ok || asset.UpAxis != Zup || asset.Unit.Meter != 0.01 || asset.Title != "Cube" || len(asset.Contributor) != 1 {
+		t.Error("wrong node asset", asset)
+	}
+	if asset, ok = collada.EffectiveAsset(node.InstanceGeometry[0]); !ok || asset.Unit.Meter != 0.01 {
+		t.Error("nested unit not inherited", asset)
+	}
+	if _, ok := collada.EffectiveAsset(&Node{}); ok {
+		t.Error("found element outside the document")
+	}
+	buffer := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buffer.String(), "<created>2013-09-18T14:30:05</created>") {
+		t.Error("created time not preserved")
+	}
+	if strings.Count(buffer.String(), "<created>") != 1 || strings.Count(buffer.String(), "<modified>") != 1 {
+		t.Error("unset created and modified times written for the node asset")
+	}
+}
+
+func TestInvalidAssetTime(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(`<COLLADA version="1.4.1"><asset><created>last tuesday</created><modified>2013-09-18T14:30:05Z</modified></asset></COLLADA>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !collada.Asset.Created.IsZero() || collada.Asset.Modified.IsZero() {
+		t.Error("wrong times", collada.Asset.Created, collada.Asset.Modified)
+	}
+	buffer := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buffer.String(), "<created>last tuesday</created>") {
+		t.Error("invalid created time not written back")
+	}
+}
@@ -0,0 +1,181 @@
+package collada
+
+import (
+	"fmt"
+	"math"
+)
+
+//MeshExportOptions configures the STL and PLY exporters.
+type MeshExportOptions struct {
+	//Scene is the visual scene to export, or nil for the default visual scene.
+	Scene *VisualScene
+	//Binary selects the binary rather than the ASCII form of the format.
+	Binary bool
+	//Meter is the length of one output unit in meters, such as 0.001 for millimeters.
+	//Zero keeps the units of the document.
+	Meter float64
+}
+
+//bakedVertex is a vertex of a scene in world space.
+type bakedVertex struct {
+	position [3]float64
+	normal   [3]float64
+	color    [4]float64
+}
+
+//bakedMesh holds the polygons of every geometry instanced by a scene in world space,
+//with vertices shared by the corners of a primitive which use the same elements.
+type bakedMesh struct {
+	vertices []bakedVertex
+	//vcount is the number of corners of each polygon.
+	vcount  []int
+	indices []int
+	//normals and colors record whether any vertex has a normal or color;
+	//vertices without one are given a zero normal or opaque white.
+	normals bool
+	colors  bool
+}
+
+//scene returns the scene selected by the options, or an error if there is none.
+func (options *MeshExportOptions) scene(collada *Collada) (*VisualScene, error) {
+	scene := options.Scene
+	if scene == nil {
+		scene = collada.DefaultVisualScene()
+	}
+	if scene == nil {
+		return nil, fmt.Errorf("collada: document has no visual scene")
+	}
+	return scene, nil
+}
+
+//scale returns the factor converting document units to the units selected by the options.
+func (options *MeshExportOptions) scale(collada *Collada) float64 {
+	if options.Meter <= 0 {
+		return 1
+	}
+	meter := 1.0
+	if collada.Asset != nil && collada.Asset.Unit != nil && collada.Asset.Unit.Meter > 0 {
+		meter = collada.Asset.Unit.Meter
+	}
+	return meter / options.Meter
+}
+
+//bake flattens the geometry instanced by the selected scene into a single world space mesh.
+func (collada *Collada) bake(options *MeshExportOptions) (*bakedMesh, error) {
+	scene, err := options.scene(collada)
+	if err != nil {
+		return nil, err
+	}
+	scale := Scaling(1, 1, 1)
+	if s := options.scale(collada); s != 1 {
+		scale = Scaling(s, s, s)
+	}
+	mesh := &bakedMesh{}
+	collada.VisitNodes(scene, func(node *Node, world Mat4) {
+		for _, instance := range node.InstanceGeometry {
+			if err == nil {
+				err = mesh.add(collada, instance, scale.Mul(world))
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mesh, nil
+}
+
+//add appends the primitives of an instanced geometry transformed by world.
+func (mesh *bakedMesh) add(collada *Collada, instance *InstanceGeometry, world Mat4) error {
+	id, ok := instance.Url.Id()
+	if !ok {
+		return nil
+	}
+	geometry := collada.FindGeometry(id)
+	if geometry == nil || geometry.Mesh == nil {
+		return nil
+	}
+	primitives, err := geometry.Mesh.Primitives()
+	if err != nil {
+		return err
+	}
+	normalMatrix := world.NormalMatrix()
+	for _, primitive := range primitives {
+		positions := primitive.Attribute("POSITION")
+		if positions == nil {
+			continue
+		}
+		normals := primitive.Attribute("NORMAL")
+		colors := primitive.Attribute("COLOR")
+		mesh.normals = mesh.normals || normals != nil
+		mesh.colors = mesh.colors || colors != nil
+		type key struct{ position, normal, color int }
+		shared := make(map[key]int)
+		for corner, position := range positions.Indices {
+			k := key{position, -1, -1}
+			if normals != nil {
+				k.normal = normals.Indices[corner]
+			}
+			if colors != nil {
+				k.color = colors.Indices[corner]
+			}
+			index, ok := shared[k]
+			if !ok {
+				index = len(mesh.vertices)
+				shared[k] = index
+				mesh.vertices = append(mesh.vertices, bakedVertex{
+					position: world.transformElement(positions.Element(corner)),
+					normal:   bakedNormal(normalMatrix, normals, corner),
+					color:    bakedColor(colors, corner),
+				})
+			}
+			mesh.indices = append(mesh.indices, index)
+		}
+		mesh.vcount = append(mesh.vcount, primitive.VCount...)
+	}
+	return nil
+}
+
+//transformElement transforms the point held by the first three components of an element.
+func (m Mat4) transformElement(e []float64) [3]float64 {
+	x, y, z := m.TransformPoint(floatsAt(e, 0), floatsAt(e, 1), floatsAt(e, 2))
+	return [3]float64{x, y, z}
+}
+
+//bakedNormal returns the normal of a corner transformed by normalMatrix, or zero if there are no normals.
+func bakedNormal(normalMatrix Mat4, normals *Attribute, corner int) [3]float64 {
+	if normals == nil {
+		return [3]float64{}
+	}
+	e := normals.Element(corner)
+	x, y, z := normalize(normalMatrix.TransformVector(floatsAt(e, 0), floatsAt(e, 1), floatsAt(e, 2)))
+	return [3]float64{x, y, z}
+}
+
+//bakedColor returns the rgba color of a corner, treating a missing alpha as opaque.
+func bakedColor(colors *Attribute, corner int) [4]float64 {
+	color := [4]float64{1, 1, 1, 1}
+	if colors != nil {
+		copy(color[:], colors.Element(corner))
+	}
+	return color
+}
+
+//triangles calls visit with the corner indices of every triangle of a fan over each polygon.
+func (mesh *bakedMesh) triangles(visit func(a, b, c int)) {
+	first := 0
+	for _, n := range mesh.vcount {
+		for i := 2; i < n; i++ {
+			visit(mesh.indices[first], mesh.indices[first+i-1], mesh.indices[first+i])
+		}
+		first += n
+	}
+}
+
+//faceNormal returns the unit normal of a counter-clockwise triangle, or zero if it is degenerate.
+func faceNormal(a, b, c [3]float64) [3]float64 {
+	x, y, z := cross(b[0]-a[0], b[1]-a[1], b[2]-a[2], c[0]-a[0], c[1]-a[1], c[2]-a[2])
+	if length := math.Sqrt(x*x + y*y + z*z); length > 0 {
+		return [3]float64{x / length, y / length, z / length}
+	}
+	return [3]float64{}
+}
@@ -0,0 +1,69 @@
+package collada
+
+import (
+	"fmt"
+)
+
+//MaterialBinding is the material bound to the symbol of a primitive by an <instance_material>.
+type MaterialBinding struct {
+	Instance *InstanceMaterialGeometry
+	Material *Material
+	Effect   *Effect
+}
+
+//MaterialBinding resolves the material bound to a primitive's material symbol by the <bind_material>
+//of an <instance_geometry> or <instance_controller>.
+func (collada *Collada) MaterialBinding(bind *BindMaterial, symbol string) (*MaterialBinding, error) {
+	if bind == nil {
+		return nil, fmt.Errorf("collada: no material bound to symbol %q", symbol)
+	}
+	for _, instance := range bind.TechniqueCommon.InstanceMaterial {
+		if instance.Symbol != symbol {
+			continue
+		}
+		id, ok := instance.Target.Id()
+		if !ok {
+			return nil, fmt.Errorf("collada: symbol %q is bound to external material %q", symbol, instance.Target)
+		}
+		material := collada.FindMaterial(id)
+		if material == nil {
+			return nil, fmt.Errorf("collada: symbol %q is bound to missing material %q", symbol, id)
+		}
+		binding := &MaterialBinding{Instance: instance, Material: material}
+		if id, ok := material.InstanceEffect.Url.Id(); ok {
+			binding.Effect = collada.FindEffect(id)
+		}
+		return binding, nil
+	}
+	return nil, fmt.Errorf("collada: no material bound to symbol %q", symbol)
+}
+
+//TexcoordSet returns the set of the TEXCOORD input which the effect reads through the texcoord
+//semantic of a <texture> element, or false if the semantic is not bound.
+func (binding *MaterialBinding) TexcoordSet(semantic string) (uint, bool) {
+	for _, input := range binding.Instance.BindVertexInput {
+		if input.Semantic != semantic || (input.InputSemantic != "" && input.InputSemantic != "TEXCOORD") {
+			continue
+		}
+		if input.InputSet == nil {
+			return 0, true
+		}
+		return *input.InputSet, true
+	}
+	return 0, false
+}
+
+//Texcoords returns the texture coordinate attribute of a primitive which the effect reads through
+//the texcoord semantic of a <texture> element, falling back to the lowest set if the semantic is unbound.
+func (binding *MaterialBinding) Texcoords(primitive *Primitive, semantic string) *Attribute {
+	set, ok := binding.TexcoordSet(semantic)
+	if !ok {
+		return primitive.Attribute("TEXCOORD")
+	}
+	for _, attribute := range primitive.Attributes {
+		if attribute.Semantic == "TEXCOORD" && attribute.Set == set {
+			return attribute
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package collada
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const bindingDocument = `<?xml version="1.0" encoding="utf-8"?>
+<COLLADA xmlns="http://www.collada.org/2008/03/COLLADASchema" version="1.5.0">
+  <library_effects>
+    <effect id="wood-fx"><profile_COMMON><technique sid="common"><lambert/></technique></profile_COMMON></effect>
+  </library_effects>
+  <library_materials>
+    <material id="wood"><instance_effect url="#wood-fx"/></material>
+  </library_materials>
+  <library_geometries>
+    <geometry id="quad">
+      <mesh>
+        <source id="quad-positions">
+          <float_array id="quad-positions-array" count="9">0 0 0 1 0 0 0 1 0</float_array>
+          <technique_common>
+            <accessor source="#quad-positions-array" count="3" stride="3">
+              <param name="X" type="float"/><param name="Y" type="float"/><param name="Z" type="float"/>
+            </accessor>
+          </technique_common>
+        </source>
+        <source id="quad-uv">
+          <float_array id="quad-uv-array" count="6">0 0 1 0 0 1</float_array>
+          <technique_common>
+            <accessor source="#quad-uv-array" count="3" stride="2">
+              <param name="S" type="float"/><param name="T" type="float"/>
+            </accessor>
+          </technique_common>
+        </source>
+        <vertices id="quad-vertices"><input semantic="POSITION" source="#quad-positions"/></vertices>
+        <triangles material="surface" count="1">
+          <input semantic="VERTEX" source="#quad-vertices" offset="0"/>
+          <input semantic="TEXCOORD" source="#quad-uv" offset="0" set="0"/>
+          <input semantic="TEXCOORD" source="#quad-uv" offset="0" set="1"/>
+          <p>0 1 2</p>
+        </triangles>
+      </mesh>
+    </geometry>
+  </library_geometries>
+  <library_visual_scenes>
+    <visual_scene id="scene">
+      <node id="quad-node">
+        <instance_geometry url="#quad">
+          <bind_material>
+            <technique_common>
+              <instance_material symbol="surface" target="#wood">
+                <bind semantic="WORLD" target="quad-node/transform"/>
+                <bind_vertex_input semantic="UVMap" input_semantic="TEXCOORD" input_set="1"/>
+              </instance_material>
+            </technique_common>
+          </bind_material>
+        </instance_geometry>
+      </node>
+    </visual_scene>
+  </library_visual_scenes>
+</COLLADA>`
+
+func TestMaterialBinding(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(bindingDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance := collada.FindNode("quad-node").InstanceGeometry[0]
+	primitives, err := collada.FindGeometry("quad").Mesh.Primitives()
+	if err != nil {
+		t.Fatal(err)
+	}
+	binding, err := collada.MaterialBinding(instance.BindMaterial, primitives[0].Material)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binding.Material.Id != "wood" || binding.Effect == nil || binding.Effect.Id != "wood-fx" {
+		t.Error("wrong material bound")
+	}
+	if bind := binding.Instance.Bind; len(bind) != 1 || bind[0].Target != "quad-node/transform" {
+		t.Error("wrong parameter binding", bind)
+	}
+	if set, ok := binding.TexcoordSet("UVMap"); !ok || set != 1 {
+		t.Error("wrong texcoord set", set, ok)
+	}
+	if texcoords := binding.Texcoords(primitives[0], "UVMap"); texcoords == nil || texcoords.Set != 1 {
+		t.Error("wrong texcoords bound")
+	}
+	if texcoords := binding.Texcoords(primitives[0], "CHANNEL2"); texcoords == nil || texcoords.Set != 0 {
+		t.Error("unbound texcoords not defaulted")
+	}
+	if _, err := collada.MaterialBinding(instance.BindMaterial, "missing"); err == nil {
+		t.Error("unbound symbol resolved")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `<bind_vertex_input semantic="UVMap" input_semantic="TEXCOORD" input_set="1"></bind_vertex_input>`) {
+		t.Error("vertex input binding not exported", buf.String())
+	}
+}
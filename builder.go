@@ -0,0 +1,281 @@
+package collada
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+//Document builds a Collada document, generating the ids, sources, accessors, vertices and
+//primitives required by the schema.
+type Document struct {
+	Collada *Collada
+	Scene   *VisualScene
+	ids     map[Id]bool
+}
+
+//NewDocument creates an empty Y_UP document measured in meters with a single visual scene.
+func NewDocument() *Document {
+	now := time.Now().UTC().Truncate(time.Second)
+	doc := &Document{
+		Collada: &Collada{
+			Xmlns:   "http://www.collada.org/2008/03/COLLADASchema",
+			Version: Version1_5_0,
+			HasAsset: HasAsset{&Asset{
+				Created:  now,
+				Modified: now,
+				Unit:     &Unit{HasName: HasName{"meter"}, Meter: 1},
+				UpAxis:   Yup,
+			}},
+			LibraryEffects:      []*LibraryEffects{{}},
+			LibraryMaterials:    []*LibraryMaterials{{}},
+			LibraryGeometries:   []*LibraryGeometries{{}},
+			LibraryVisualScenes: []*LibraryVisualScenes{{}},
+		},
+		ids: make(map[Id]bool),
+	}
+	doc.Scene = &VisualScene{HasId: HasId{doc.newId("Scene")}, HasName: HasName{"Scene"}}
+	doc.Collada.LibraryVisualScenes[0].VisualScene = []*VisualScene{doc.Scene}
+	doc.Collada.Scene = &Scene{
+		InstanceVisualScene: &InstanceVisualScene{HasUrl: HasUrl{doc.Scene.Id.Uri()}},
+	}
+	return doc
+}
+
+var invalidIdCharacters = regexp.MustCompile("[^\\w-]+")
+
+//newId returns a unique id derived from name.
+func (doc *Document) newId(name string) Id {
+	base := invalidIdCharacters.ReplaceAllString(name, "_")
+	if base == "" {
+		base = "id"
+	}
+	id := Id(base)
+	for i := 1; doc.ids[id]; i++ {
+		id = Id(base + "-" + strconv.Itoa(i))
+	}
+	doc.ids[id] = true
+	return id
+}
+
+//Uri returns a reference to the element with this id.
+func (id Id) Uri() Uri {
+	return Uri("#" + id)
+}
+
+//AddGeometry adds a triangle mesh geometry.
+//Positions and normals hold three components per vertex and uvs two; normals and uvs may be nil.
+//Each index refers to the same vertex in every array, and every three indices form a triangle.
+func (doc *Document) AddGeometry(name string, positions, normals, uvs []float64, indices []int) (*Geometry, error) {
+	if len(positions)%3 != 0 {
+		return nil, fmt.Errorf("collada: %d position components is not a multiple of 3", len(positions))
+	}
+	count := len(positions) / 3
+	if normals != nil && len(normals) != count*3 {
+		return nil, fmt.Errorf("collada: %d normal components for %d vertices", len(normals), count)
+	}
+	if uvs != nil && len(uvs) != count*2 {
+		return nil, fmt.Errorf("collada: %d uv components for %d vertices", len(uvs), count)
+	}
+	if len(indices)%3 != 0 {
+		return nil, fmt.Errorf("collada: %d indices is not a multiple of 3", len(indices))
+	}
+	p := make(IntValues, len(indices))
+	for i, index := range indices {
+		if index < 0 || index >= count {
+			return nil, fmt.Errorf("collada: index %d out of range for %d vertices", index, count)
+		}
+		p[i] = int32(index)
+	}
+	id := doc.newId(name + "-mesh")
+	mesh := &Mesh{}
+	mesh.Vertices = Vertices{HasId: HasId{doc.newId(string(id) + "-vertices")}}
+	addSource := func(suffix, semantic string, values []float64, params ...string) {
+		source := doc.newSource(string(id)+"-"+suffix, values, params...)
+		mesh.Source = append(mesh.Source, source)
+		mesh.Vertices.Input = append(mesh.Vertices.Input, &InputUnshared{Semantic: semantic, Source: source.Id.Uri()})
+	}
+	addSource("positions", "POSITION", positions, "X", "Y", "Z")
+	if normals != nil {
+		addSource("normals", "NORMAL", normals, "X", "Y", "Z")
+	}
+	if uvs != nil {
+		addSource("map", "TEXCOORD", uvs, "S", "T")
+	}
+	triangles := &Triangles{
+		HasCount:       HasCount{len(indices) / 3},
+		HasMaterial:    HasMaterial{string(id) + "-material"},
+		HasSharedInput: HasSharedInput{[]*InputShared{{Semantic: "VERTEX", Source: mesh.Vertices.Id.Uri()}}},
+		HasP:           HasP{&P{Ints{p}}},
+	}
+	mesh.Triangles = []*Triangles{triangles}
+	geometry := &Geometry{HasId: HasId{id}, HasName: HasName{name}, Mesh: mesh}
+	library := doc.Collada.LibraryGeometries[0]
+	library.Geometry = append(library.Geometry, geometry)
+	return geometry, nil
+}
+
+//newSource creates a float source read with one named float param per component.
+func (doc *Document) newSource(name string, values []float64, params ...string) *Source {
+	id := doc.newId(name)
+	return floatSource(id, doc.newId(string(id)+"-array"), values, params...)
+}
+
+//floatSource creates a float source with the given ids, read with one named float param per component.
+func floatSource(id, arrayId Id, values []float64, params ...string) *Source {
+	array := &FloatArray{
+		HasId:    HasId{arrayId},
+		HasCount: HasCount{len(values)},
+		Floats:   Floats{append(FloatValues(nil), values...)},
+	}
+	accessor := Accessor{
+		HasCount: HasCount{len(values) / len(params)},
+		Source:   array.Id.Uri(),
+		Stride:   uint(len(params)),
+	}
+	for _, param := range params {
+		accessor.Param = append(accessor.Param, &ParamCore{HasName: HasName{param}, Type: "float"})
+	}
+	return &Source{
+		HasId:           HasId{id},
+		FloatArray:      array,
+		TechniqueCommon: &SourceTechniqueCommon{accessor},
+	}
+}
+
+//AddMaterial adds a material with a phong effect of the given diffuse rgba color.
+func (doc *Document) AddMaterial(name string, diffuse [4]float64) *Material {
+	return doc.AddPhongMaterial(name, &Phong{Diffuse: NewColor("diffuse", diffuse)})
+}
+
+//AddPhongMaterial adds a material with a profile_COMMON effect shaded by phong.
+func (doc *Document) AddPhongMaterial(name string, phong *Phong) *Material {
+	effect := &Effect{
+		HasId: HasId{doc.newId(name + "-effect")},
+		ProfileCommon: &ProfileCommon{
+			HasTechniqueFx: HasTechniqueFx{[]*TechniqueFx{{
+				HasSid: HasSid{"common"},
+				Phong:  phong,
+			}}},
+		},
+	}
+	effects := doc.Collada.LibraryEffects[0]
+	effects.Effect = append(effects.Effect, effect)
+	material := &Material{
+		HasId:          HasId{doc.newId(name + "-material")},
+		HasName:        HasName{name},
+		InstanceEffect: InstanceEffect{HasUrl: HasUrl{effect.Id.Uri()}},
+	}
+	materials := doc.Collada.LibraryMaterials[0]
+	materials.Material = append(materials.Material, material)
+	return material
+}
+
+//NewColor creates a fixed-function shader color attribute.
+func NewColor(sid string, rgba [4]float64) *FxCommonColorOrTextureType {
+	return &FxCommonColorOrTextureType{Color: &Color{HasSid{sid}, Float3{Floats{rgba[:]}}}}
+}
+
+//NewFloat creates a fixed-function shader float attribute.
+func NewFloat(sid string, value float64) *FxCommonFloatOrParamType {
+	return &FxCommonFloatOrParamType{Float: &Float{HasSid{sid}, value}}
+}
+
+//AddNode adds a node beneath parent, or at the root of the scene if parent is nil.
+func (doc *Document) AddNode(name string, parent *Node) *Node {
+	node := &Node{
+		HasId:   HasId{doc.newId(name)},
+		HasName: HasName{name},
+		HasType: HasType{"NODE"},
+	}
+	if parent == nil {
+		doc.Scene.Node = append(doc.Scene.Node, node)
+	} else {
+		parent.Node = append(parent.Node, node)
+	}
+	return node
+}
+
+//Instance places geometry at node, binding every material symbol of its primitives to material.
+//The material may be nil, leaving the geometry unbound.
+func (doc *Document) Instance(node *Node, geometry *Geometry, material *Material) *InstanceGeometry {
+	instance := &InstanceGeometry{HasUrl: HasUrl{geometry.Id.Uri()}}
+	if material != nil {
+		for _, symbol := range geometry.materialSymbols() {
+			doc.Bind(instance, symbol, material)
+		}
+	}
+	node.InstanceGeometry = append(node.InstanceGeometry, instance)
+	return instance
+}
+
+//Bind binds a single material symbol of an instanced geometry to material.
+func (doc *Document) Bind(instance *InstanceGeometry, symbol string, material *Material) {
+	if instance.BindMaterial == nil {
+		instance.BindMaterial = &BindMaterial{}
+	}
+	common := &instance.BindMaterial.TechniqueCommon
+	common.InstanceMaterial = append(common.InstanceMaterial, &InstanceMaterialGeometry{
+		Symbol: symbol,
+		Target: material.Id.Uri(),
+	})
+}
+
+//materialSymbols lists the distinct material symbols used by the primitives of a mesh.
+func (geometry *Geometry) materialSymbols() []string {
+	symbols := []string{}
+	if geometry.Mesh == nil {
+		return symbols
+	}
+	seen := make(map[string]bool)
+	add := func(symbol string) {
+		if symbol != "" && !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	mesh := geometry.Mesh
+	for _, p := range mesh.Lines {
+		add(p.Material)
+	}
+	for _, p := range mesh.Linestrips {
+		add(p.Material)
+	}
+	for _, p := range mesh.Polygons {
+		add(p.Material)
+	}
+	for _, p := range mesh.Polylist {
+		add(p.Material)
+	}
+	for _, p := range mesh.Triangles {
+		add(p.Material)
+	}
+	for _, p := range mesh.Trifans {
+		add(p.Material)
+	}
+	for _, p := range mesh.Tristrips {
+		add(p.Material)
+	}
+	return symbols
+}
+
+//NewTranslate creates a translation by (x, y, z).
+func NewTranslate(x, y, z float64) *Translate {
+	return &Translate{Float3: Float3{Floats{FloatValues{x, y, z}}}}
+}
+
+//NewRotate creates a rotation of angle degrees around the axis (x, y, z).
+func NewRotate(x, y, z, angle float64) *Rotate {
+	return &Rotate{Float4: Float4{Floats{FloatValues{x, y, z, angle}}}}
+}
+
+//NewScale creates a scale by (x, y, z).
+func NewScale(x, y, z float64) *Scale {
+	return &Scale{Float3: Float3{Floats{FloatValues{x, y, z}}}}
+}
+
+//NewMatrix creates a transform from a row-major 4x4 matrix.
+func NewMatrix(m [16]float64) *Matrix {
+	return &Matrix{Float4x4: Float4x4{Floats{append(FloatValues(nil), m[:]...)}}}
+}
@@ -0,0 +1,63 @@
+package collada
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestBuildDocument(t *testing.T) {
+	doc := NewDocument()
+	positions := []float64{0, 0, 0, 1, 0, 0, 0, 1, 0}
+	normals := []float64{0, 0, 1, 0, 0, 1, 0, 0, 1}
+	uvs := []float64{0, 0, 1, 0, 0, 1}
+	geometry, err := doc.AddGeometry("Triangle", positions, normals, uvs, []int{0, 1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doc.AddGeometry("Broken", positions, nil, nil, []int{0, 1, 3}); err == nil {
+		t.Error("expected out of range index error")
+	}
+	material := doc.AddMaterial("Red", [4]float64{1, 0, 0, 1})
+	parent := doc.AddNode("Group", nil)
+	parent.Translate = append(parent.Translate, NewTranslate(1, 2, 3))
+	node := doc.AddNode("Triangle", parent)
+	doc.Instance(node, geometry, material)
+	doc.Instance(doc.AddNode("Triangle", parent), geometry, material)
+
+	buffer := &bytes.Buffer{}
+	if err := doc.Collada.ExportToWriter(buffer); err != nil {
+		t.Fatal(err)
+	}
+	collada, err := LoadDocumentFromReader(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := collada.LibraryGeometries[0].Geometry[0]
+	if loaded.Id != "Triangle-mesh" {
+		t.Error("wrong geometry id", loaded.Id)
+	}
+	mesh := loaded.Mesh
+	if len(mesh.Source) != 3 || len(mesh.Vertices.Input) != 3 {
+		t.Fatal("wrong number of sources", len(mesh.Source))
+	}
+	if !reflect.DeepEqual(mesh.Source[0].FloatArray.F(), positions) {
+		t.Error("wrong positions", mesh.Source[0].FloatArray.F())
+	}
+	accessor := mesh.Source[2].TechniqueCommon.Accessor
+	if accessor.Count != 3 || accessor.Stride != 2 || accessor.Source != "#Triangle-mesh-map-array" {
+		t.Error("wrong uv accessor", accessor)
+	}
+	triangles := mesh.Triangles[0]
+	if triangles.Count != 1 || triangles.Input[0].Source != mesh.Vertices.Id.Uri() {
+		t.Error("wrong triangles", triangles)
+	}
+	nodes := collada.LibraryVisualScenes[0].VisualScene[0].Node[0].Node
+	if len(nodes) != 2 || nodes[0].Id == nodes[1].Id {
+		t.Fatal("node ids are not unique")
+	}
+	bound := nodes[1].InstanceGeometry[0].BindMaterial.TechniqueCommon.InstanceMaterial[0]
+	if bound.Symbol != triangles.Material || bound.Target != "#Red-material" {
+		t.Error("wrong material binding", bound)
+	}
+}
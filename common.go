@@ -0,0 +1,191 @@
+package collada
+
+//ShadingModel names the fixed-function shader of a profile_COMMON technique.
+type ShadingModel string
+
+const (
+	ShadingConstant ShadingModel = "constant"
+	ShadingLambert  ShadingModel = "lambert"
+	ShadingPhong    ShadingModel = "phong"
+	ShadingBlinn    ShadingModel = "blinn"
+)
+
+//CommonColor is a color attribute of a fixed-function shader, given by exactly one of
+//a literal color, a texture or a reference to a parameter.
+type CommonColor struct {
+	Opaque Opaque
+	//Color is nil unless the attribute is a literal color or a parameter which resolves to one.
+	Color   []float64
+	Texture *Texture
+	Param   string
+}
+
+//CommonFloat is a scalar attribute of a fixed-function shader, given by a literal value or a reference to a parameter.
+type CommonFloat struct {
+	//Value is nil unless the attribute is a literal value or a parameter which resolves to one.
+	Value *float64
+	Param string
+}
+
+//CommonMaterial is a uniform view of the profile_COMMON technique of a material's effect,
+//whatever its shading model. Attributes the technique does not set, or which the model lacks, are nil.
+type CommonMaterial struct {
+	Material  *Material
+	Effect    *Effect
+	Technique *TechniqueFx
+	Model     ShadingModel
+	//Scope resolves the textures and parameters referenced by the technique.
+	Scope *ParamScope
+
+	Emission    *CommonColor
+	Ambient     *CommonColor
+	Diffuse     *CommonColor
+	Specular    *CommonColor
+	Reflective  *CommonColor
+	Transparent *CommonColor
+
+	Shininess         *CommonFloat
+	Reflectivity      *CommonFloat
+	Transparency      *CommonFloat
+	IndexOfRefraction *CommonFloat
+}
+
+//CommonMaterial returns the fixed-function shading of a material, or nil if its effect has no profile_COMMON technique.
+//Parameters holding colors and values are resolved; those which cannot be are left as references.
+func (collada *Collada) CommonMaterial(material *Material) *CommonMaterial {
+	scope, err := collada.ParamScope(material, nil)
+	if err != nil || scope.Effect.ProfileCommon == nil || len(scope.Effect.ProfileCommon.TechniqueFx) == 0 {
+		return nil
+	}
+	technique := scope.Effect.ProfileCommon.TechniqueFx[0]
+	scope.Technique = technique
+	common := &CommonMaterial{Material: material, Effect: scope.Effect, Technique: technique, Scope: scope}
+	switch {
+	case technique.ConstantFx != nil:
+		c := technique.ConstantFx
+		common.Model = ShadingConstant
+		common.Emission = c.Emission.common()
+		common.Reflective, common.Reflectivity = c.Reflective.common(), c.Reflectivity.common()
+		common.Transparent, common.Transparency = c.Transparent.common(), c.Transparency.common()
+		common.IndexOfRefraction = c.IndexOfRefraction.common()
+	case technique.Lambert != nil:
+		l := technique.Lambert
+		common.Model = ShadingLambert
+		common.Emission, common.Ambient, common.Diffuse = l.Emission.common(), l.AmbientFx.common(), l.Diffuse.common()
+		common.Reflective, common.Reflectivity = l.Reflective.common(), l.Reflectivity.common()
+		common.Transparent, common.Transparency = l.Transparent.common(), l.Transparency.common()
+		common.IndexOfRefraction = l.IndexOfRefraction.common()
+	case technique.Phong != nil:
+		p := technique.Phong
+		common.Model = ShadingPhong
+		common.Emission, common.Ambient, common.Diffuse = p.Emission.common(), p.AmbientFx.common(), p.Diffuse.common()
+		common.Specular, common.Shininess = p.Specular.common(), p.Shininess.common()
+		common.Reflective, common.Reflectivity = p.Reflective.common(), p.Reflectivity.common()
+		common.Transparent, common.Transparency = p.Transparent.common(), p.Transparency.common()
+		common.IndexOfRefraction = p.IndexOfRefraction.common()
+	case technique.Blinn != nil:
+		b := technique.Blinn
+		common.Model = ShadingBlinn
+		common.Emission, common.Ambient, common.Diffuse = b.Emission.common(), b.AmbientFx.common(), b.Diffuse.common()
+		common.Specular, common.Shininess = b.Specular.common(), b.Shininess.common()
+		common.Reflective, common.Reflectivity = b.Reflective.common(), b.Reflectivity.common()
+		common.Transparent, common.Transparency = b.Transparent.common(), b.Transparency.common()
+		common.IndexOfRefraction = b.IndexOfRefraction.common()
+	default:
+		return nil
+	}
+	for _, c := range []*CommonColor{common.Emission, common.Ambient, common.Diffuse, common.Specular, common.Reflective, common.Transparent} {
+		if c == nil || c.Param == "" {
+			continue
+		}
+		if value, err := scope.Param(c.Param); err == nil {
+			if floats := value.Floats(); len(floats) == 3 || len(floats) == 4 {
+				c.Color = append([]float64(nil), floats...)
+			}
+		}
+	}
+	for _, f := range []*CommonFloat{common.Shininess, common.Reflectivity, common.Transparency, common.IndexOfRefraction} {
+		if f == nil || f.Param == "" {
+			continue
+		}
+		if value, err := scope.Param(f.Param); err == nil {
+			if floats := value.Floats(); len(floats) == 1 {
+				v := floats[0]
+				f.Value = &v
+			}
+		}
+	}
+	return common
+}
+
+func (c *FxCommonColorOrTextureType) common() *CommonColor {
+	if c == nil {
+		return nil
+	}
+	common := &CommonColor{Opaque: c.Opaque, Texture: c.Texture}
+	if c.Color != nil {
+		common.Color = append([]float64(nil), c.Color.F()...)
+	}
+	if c.Param != nil {
+		common.Param = c.Param.Ref
+	}
+	return common
+}
+
+func (f *FxCommonFloatOrParamType) common() *CommonFloat {
+	switch {
+	case f == nil:
+		return nil
+	case f.Float != nil:
+		value := f.Float.Value
+		return &CommonFloat{Value: &value}
+	case f.Param != nil:
+		return &CommonFloat{Param: f.Param.Ref}
+	}
+	return nil
+}
+
+//RGBA returns the color with alpha defaulting to one, or false if the attribute is not a color.
+func (c *CommonColor) RGBA() ([4]float64, bool) {
+	rgba := [4]float64{0, 0, 0, 1}
+	if c == nil || c.Color == nil {
+		return rgba, false
+	}
+	copy(rgba[:], c.Color)
+	return rgba, true
+}
+
+//Float returns the value, or false if the attribute is missing or an unresolved parameter reference.
+func (f *CommonFloat) Float() (float64, bool) {
+	if f == nil || f.Value == nil {
+		return 0, false
+	}
+	return *f.Value, true
+}
+
+//Opacity combines <transparent> and <transparency> into a single opacity according to the opaque mode,
+//or returns false if neither is known.
+func (common *CommonMaterial) Opacity() (float64, bool) {
+	color, hasColor := common.Transparent.RGBA()
+	factor, hasFactor := common.Transparency.Float()
+	if !hasColor && !hasFactor {
+		return 1, false
+	}
+	if !hasFactor {
+		factor = 1
+	}
+	opaque := Opaque(OpaqueAlphaOne)
+	if common.Transparent != nil && common.Transparent.Opaque != "" {
+		opaque = common.Transparent.Opaque
+	}
+	luminance := color[0]*0.212671 + color[1]*0.715160 + color[2]*0.072169
+	switch opaque {
+	case OpaqueAlphaZero:
+		return 1 - color[3]*factor, true
+	case OpaqueRgbZero:
+		return 1 - luminance*factor, true
+	case OpaqueRgbOne:
+		return luminance * factor, true
+	}
+	return color[3] * factor, true
+}
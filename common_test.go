@@ -0,0 +1,98 @@
+package collada
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const commonDocument = `<?xml version="1.0" encoding="utf-8"?>
+<COLLADA xmlns="http://www.collada.org/2008/03/COLLADASchema" version="1.5.0">
+  <library_effects>
+    <effect id="glow-fx">
+      <profile_COMMON>
+        <technique sid="common">
+          <constant>
+            <emission><color>1 0.5 0 1</color></emission>
+            <transparent opaque="RGB_ZERO"><color>0.5 0.5 0.5 1</color></transparent>
+            <transparency><float>1</float></transparency>
+          </constant>
+        </technique>
+      </profile_COMMON>
+    </effect>
+    <effect id="matte-fx">
+      <profile_COMMON>
+        <technique sid="common">
+          <lambert>
+            <diffuse><texture texture="wood-sampler" texcoord="UVMap"/></diffuse>
+            <transparency><param ref="fade"/></transparency>
+          </lambert>
+        </technique>
+      </profile_COMMON>
+    </effect>
+    <effect id="shiny-fx">
+      <profile_COMMON>
+        <technique sid="common">
+          <blinn>
+            <diffuse><param ref="tint"/></diffuse>
+            <specular><color>1 1 1 1</color></specular>
+            <shininess><float>20</float></shininess>
+          </blinn>
+        </technique>
+      </profile_COMMON>
+    </effect>
+  </library_effects>
+  <library_materials>
+    <material id="glow"><instance_effect url="#glow-fx"/></material>
+    <material id="matte"><instance_effect url="#matte-fx"/></material>
+    <material id="shiny"><instance_effect url="#shiny-fx"/></material>
+    <material id="missing"><instance_effect url="#missing-fx"/></material>
+  </library_materials>
+</COLLADA>`
+
+func TestCommonMaterial(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(commonDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	glow := collada.CommonMaterial(collada.FindMaterial("glow"))
+	if glow.Model != ShadingConstant || glow.Diffuse != nil {
+		t.Fatal("wrong constant material", glow.Model)
+	}
+	if emission, ok := glow.Emission.RGBA(); !ok || emission != [4]float64{1, 0.5, 0, 1} {
+		t.Error("wrong emission", emission)
+	}
+	if opacity, ok := glow.Opacity(); !ok || opacity < 0.49 || opacity > 0.51 {
+		t.Error("wrong RGB_ZERO opacity", opacity)
+	}
+
+	matte := collada.CommonMaterial(collada.FindMaterial("matte"))
+	if matte.Model != ShadingLambert || matte.Diffuse.Texture == nil || matte.Diffuse.Texture.TexCoord != "UVMap" {
+		t.Fatal("wrong lambert material", matte.Model)
+	}
+	if _, ok := matte.Diffuse.RGBA(); ok {
+		t.Error("texture read as a color")
+	}
+	if _, ok := matte.Opacity(); ok || matte.Transparency.Param != "fade" {
+		t.Error("parameter read as a value")
+	}
+
+	shiny := collada.CommonMaterial(collada.FindMaterial("shiny"))
+	if shiny.Model != ShadingBlinn || shiny.Diffuse.Param != "tint" {
+		t.Fatal("wrong blinn material", shiny.Model)
+	}
+	if shininess, ok := shiny.Shininess.Float(); !ok || shininess != 20 {
+		t.Error("wrong shininess", shininess)
+	}
+	if collada.CommonMaterial(collada.FindMaterial("missing")) != nil {
+		t.Error("material without an effect has common shading")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `<param ref="tint"></param>`) || !strings.Contains(buf.String(), "<constant>") {
+		t.Error("shading not exported", buf.String())
+	}
+}
@@ -0,0 +1,229 @@
+package collada
+
+import (
+	"encoding"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"sync"
+)
+
+//elementDecoder is implemented by the types with an UnmarshalXML method. They decode their children
+//through decode, so that LoadDocumentWithOptions can defer the conversion of the arrays the children hold.
+type elementDecoder interface {
+	unmarshalXML(d *xml.Decoder, start xml.StartElement, decode func(v interface{}, start *xml.StartElement) error) error
+}
+
+//arrayDecoder decodes a document for LoadDocumentWithOptions, keeping the text of its float and int
+//arrays to be converted once the whole document has been read. Elements which hold no arrays are
+//handed to the xml.Decoder unchanged.
+type arrayDecoder struct {
+	d    *xml.Decoder
+	jobs []func() error
+}
+
+var (
+	intValuesType      = reflect.TypeOf(IntValues{})
+	elementDecoderType = reflect.TypeOf((*elementDecoder)(nil)).Elem()
+	arrayTypes         sync.Map
+)
+
+//isArray reports whether values of type t are converted from text by the workers.
+func isArray(t reflect.Type) bool {
+	return t == floatValuesType || t == intValuesType
+}
+
+//holdsArrays reports whether values of type t can hold float or int arrays, or are read by an elementDecoder.
+func holdsArrays(t reflect.Type) bool {
+	if holds, ok := arrayTypes.Load(t); ok {
+		return holds.(bool)
+	}
+	holds := reaches(t, func(t reflect.Type) bool {
+		return isArray(t) || reflect.PtrTo(t).Implements(elementDecoderType)
+	}, make(map[reflect.Type]bool))
+	arrayTypes.Store(t, holds)
+	return holds
+}
+
+//decodeDocument decodes the root element of a document into v.
+func (a *arrayDecoder) decodeDocument(v interface{}) error {
+	for {
+		token, err := a.d.Token()
+		if err != nil {
+			return err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return a.decode(v, &start)
+		}
+	}
+}
+
+func (a *arrayDecoder) decode(v interface{}, start *xml.StartElement) error {
+	return a.element(reflect.ValueOf(v).Elem(), *start)
+}
+
+//element decodes an element into v, which is addressable. An element decoded into a slice is appended to it.
+func (a *arrayDecoder) element(v reflect.Value, start xml.StartElement) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if !holdsArrays(v.Type()) {
+		return a.d.DecodeElement(v.Addr().Interface(), &start)
+	}
+	if decoder, ok := v.Addr().Interface().(elementDecoder); ok {
+		return decoder.unmarshalXML(a.d, start, a.decode)
+	}
+	if isArray(v.Type()) {
+		text, err := a.text()
+		if err == nil {
+			a.convert(text, v)
+		}
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		n := v.Len()
+		v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+		return a.element(v.Index(n), start)
+	case reflect.Struct:
+		return a.structure(v, start)
+	}
+	return a.d.DecodeElement(v.Addr().Interface(), &start)
+}
+
+//structure decodes the child elements of a struct, leaving its attributes and any text other than
+//an array to the xml.Decoder.
+func (a *arrayDecoder) structure(v reflect.Value, start xml.StartElement) error {
+	fields := xmlFields(v.Type())
+	var text []byte
+	for {
+		token, err := a.d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			field, ok := childField(fields, t.Name.Local)
+			if !ok {
+				if err := a.d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := a.element(v.FieldByIndex(field.index), t); err != nil {
+				return err
+			}
+		case xml.CharData:
+			text = append(text, t...)
+		case xml.EndElement:
+			tokens := []xml.Token{start}
+			for _, field := range fields {
+				if field.kind != fieldText {
+					continue
+				}
+				if value := v.FieldByIndex(field.index); isArray(value.Type()) {
+					a.convert(text, value)
+				} else {
+					tokens = append(tokens, xml.CharData(text))
+				}
+			}
+			tokens = append(tokens, start.End())
+			rest := xml.NewTokenDecoder(&tokenList{tokens})
+			if _, err := rest.Token(); err != nil {
+				return err
+			}
+			return rest.DecodeElement(v.Addr().Interface(), &start)
+		}
+	}
+}
+
+//childField returns the field decoding the child elements with a name.
+func childField(fields []xmlField, name string) (xmlField, bool) {
+	for _, field := range fields {
+		if field.kind == fieldElement && field.name == name {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		if field.kind == fieldAny {
+			return field, true
+		}
+	}
+	return xmlField{}, false
+}
+
+//text reads the text of the current element, skipping any child elements.
+func (a *arrayDecoder) text() ([]byte, error) {
+	var text []byte
+	for {
+		token, err := a.d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if err := a.d.Skip(); err != nil {
+				return nil, err
+			}
+		case xml.CharData:
+			text = append(text, t...)
+		case xml.EndElement:
+			if text == nil {
+				text = []byte{}
+			}
+			return text, nil
+		}
+	}
+}
+
+//convert queues the conversion of text into an array.
+func (a *arrayDecoder) convert(text []byte, array reflect.Value) {
+	values := array.Addr().Interface().(encoding.TextUnmarshaler)
+	a.jobs = append(a.jobs, func() error { return values.UnmarshalText(text) })
+}
+
+//tokenList reads a list of tokens.
+type tokenList struct {
+	tokens []xml.Token
+}
+
+func (list *tokenList) Token() (xml.Token, error) {
+	if len(list.tokens) == 0 {
+		return nil, io.EOF
+	}
+	token := list.tokens[0]
+	list.tokens = list.tokens[1:]
+	return token, nil
+}
+
+//run performs the queued conversions, sharing the work between the given number of goroutines.
+//Each array is converted independently, so neither the values nor the reported error
+//depend on the number of workers.
+func (a *arrayDecoder) run(workers int) error {
+	errs := make([]error, len(a.jobs))
+	next := make(chan int, workers)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = a.jobs[i]()
+			}
+		}()
+	}
+	for i := range a.jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
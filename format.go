@@ -0,0 +1,302 @@
+package collada
+
+import (
+	"encoding"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//elementEncoder is implemented by the types with a MarshalXML method. They encode their children
+//through encode, so that ExportWithOptions can format the floating-point values the children hold.
+type elementEncoder interface {
+	marshalXML(e *xml.Encoder, start xml.StartElement, encode func(v interface{}, start xml.StartElement) error) error
+}
+
+//floatEncoder writes a document with its floating-point values in a format. MarshalText cannot be
+//configured per export, so the encoder walks the typed fields of the document itself and formats the
+//float64 and FloatValues fields it finds. Elements which hold no floating-point values are handed to
+//the xml.Encoder unchanged.
+type floatEncoder struct {
+	e      *xml.Encoder
+	format FloatFormat
+}
+
+var (
+	floatValuesType    = reflect.TypeOf(FloatValues{})
+	elementEncoderType = reflect.TypeOf((*elementEncoder)(nil)).Elem()
+	floatTypes         sync.Map
+)
+
+//holdsFloats reports whether values of type t can hold floating-point values, or are written by an elementEncoder.
+func holdsFloats(t reflect.Type) bool {
+	if holds, ok := floatTypes.Load(t); ok {
+		return holds.(bool)
+	}
+	holds := reaches(t, func(t reflect.Type) bool {
+		return t == floatValuesType || t.Kind() == reflect.Float64 || reflect.PtrTo(t).Implements(elementEncoderType)
+	}, make(map[reflect.Type]bool))
+	floatTypes.Store(t, holds)
+	return holds
+}
+
+func (f *floatEncoder) encode(v interface{}, start xml.StartElement) error {
+	return f.element(reflect.ValueOf(v), start)
+}
+
+//element writes a value as an element, or as an element for each item of a slice.
+func (f *floatEncoder) element(v reflect.Value, start xml.StartElement) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !holdsFloats(v.Type()) {
+		return f.e.EncodeElement(v.Interface(), start)
+	}
+	if encoder, ok := addressable(v).Addr().Interface().(elementEncoder); ok {
+		return encoder.marshalXML(f.e, start, f.encode)
+	}
+	if text, ok := f.text(v); ok {
+		return f.e.EncodeElement(text, start)
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := f.element(v.Index(i), start); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		return f.structure(v, start)
+	}
+	return f.e.EncodeElement(v.Interface(), start)
+}
+
+//structure writes the attributes, text and child elements of a struct.
+func (f *floatEncoder) structure(v reflect.Value, start xml.StartElement) error {
+	fields := xmlFields(v.Type())
+	for _, field := range fields {
+		if field.kind != fieldAttr {
+			continue
+		}
+		value := v.FieldByIndex(field.index)
+		if field.omitEmpty && isEmpty(value) {
+			continue
+		}
+		if value = indirect(value); !value.IsValid() {
+			continue
+		}
+		text, err := f.simple(value)
+		if err != nil {
+			return err
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: field.name}, Value: text})
+	}
+	if err := f.e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, field := range fields {
+		value := v.FieldByIndex(field.index)
+		if field.omitEmpty && isEmpty(value) {
+			continue
+		}
+		switch field.kind {
+		case fieldText:
+			if value = indirect(value); !value.IsValid() {
+				continue
+			}
+			text, err := f.simple(value)
+			if err != nil {
+				return err
+			}
+			if err := f.e.EncodeToken(xml.CharData(text)); err != nil {
+				return err
+			}
+		case fieldElement, fieldAny:
+			name := field.name
+			if field.kind == fieldAny {
+				name = elementType(value.Type()).Name()
+			}
+			if err := f.element(value, xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+				return err
+			}
+		}
+	}
+	return f.e.EncodeToken(start.End())
+}
+
+//text formats a float64 or FloatValues value.
+func (f *floatEncoder) text(v reflect.Value) (string, bool) {
+	switch {
+	case v.Type() == floatValuesType:
+		values := v.Interface().(FloatValues)
+		text := make([]byte, 0, len(values)*8)
+		for i, value := range values {
+			if i > 0 {
+				text = append(text, ' ')
+			}
+			text = f.format.append(text, value)
+		}
+		return string(text), true
+	case v.Kind() == reflect.Float64:
+		return string(f.format.append(nil, v.Float())), true
+	}
+	return "", false
+}
+
+//simple returns the text of an attribute or of the text of an element, as encoding/xml writes it.
+func (f *floatEncoder) simple(v reflect.Value) (string, error) {
+	if text, ok := f.text(v); ok {
+		return text, nil
+	}
+	if marshaler, ok := addressable(v).Addr().Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	}
+	return "", fmt.Errorf("collada: cannot write %s as text", v.Type())
+}
+
+//fieldKind is how a struct field is written in xml.
+type fieldKind int
+
+const (
+	fieldElement fieldKind = iota
+	fieldAttr
+	fieldText
+	fieldAny
+	fieldOther
+)
+
+//xmlField is a field of a struct, or of a struct it embeds, as read by encoding/xml.
+type xmlField struct {
+	index     []int
+	name      string
+	kind      fieldKind
+	omitEmpty bool
+}
+
+var structFields sync.Map
+
+//xmlFields returns the fields of a struct type in order, with those of embedded structs in their place.
+func xmlFields(t reflect.Type) []xmlField {
+	if fields, ok := structFields.Load(t); ok {
+		return fields.([]xmlField)
+	}
+	fields := []xmlField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("xml")
+		if field.PkgPath != "" && !field.Anonymous || tag == "-" || field.Name == "XMLName" {
+			continue
+		}
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			for _, embedded := range xmlFields(field.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				fields = append(fields, embedded)
+			}
+			continue
+		}
+		options := strings.Split(tag, ",")
+		f := xmlField{index: []int{i}, name: options[0]}
+		if f.name == "" {
+			f.name = field.Name
+		}
+		for _, option := range options[1:] {
+			switch option {
+			case "attr":
+				f.kind = fieldAttr
+			case "chardata":
+				f.kind = fieldText
+			case "any":
+				f.kind = fieldAny
+			case "innerxml", "comment", "cdata":
+				f.kind = fieldOther
+			case "omitempty":
+				f.omitEmpty = true
+			}
+		}
+		fields = append(fields, f)
+	}
+	structFields.Store(t, fields)
+	return fields
+}
+
+//reaches reports whether values of type t can hold a value of a type for which leaf is true.
+func reaches(t reflect.Type, leaf func(reflect.Type) bool, visited map[reflect.Type]bool) bool {
+	if leaf(t) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice:
+		return reaches(t.Elem(), leaf, visited)
+	case reflect.Struct:
+		if visited[t] {
+			return false
+		}
+		visited[t] = true
+		for _, field := range xmlFields(t) {
+			if reaches(t.FieldByIndex(field.index).Type, leaf, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//addressable returns v, or an addressable copy of it.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	copied := reflect.New(v.Type()).Elem()
+	copied.Set(v)
+	return copied
+}
+
+//indirect follows pointers, returning the zero Value for a nil pointer.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+//elementType returns the type of the elements written for a field of type t.
+func elementType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t
+}
+
+//isEmpty reports whether a field marked omitempty is left out.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Ptr:
+		return v.IsZero()
+	}
+	return false
+}
@@ -0,0 +1,1426 @@
+package collada
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//The glTF 2.0 document model, limited to the parts this package reads and writes.
+
+const (
+	gltfByte          = 5120
+	gltfUnsignedByte  = 5121
+	gltfShort         = 5122
+	gltfUnsignedShort = 5123
+	gltfUnsignedInt   = 5125
+	gltfFloat         = 5126
+
+	gltfPoints        = 0
+	gltfLines         = 1
+	gltfLineLoop      = 2
+	gltfLineStrip     = 3
+	gltfTriangles     = 4
+	gltfTriangleStrip = 5
+	gltfTriangleFan   = 6
+
+	gltfArrayBuffer        = 34962
+	gltfElementArrayBuffer = 34963
+
+	glbMagic     = 0x46546C67
+	glbChunkJson = 0x4E4F534A
+	glbChunkBin  = 0x004E4942
+)
+
+type gltfDocument struct {
+	Asset          gltfAsset         `json:"asset"`
+	Scene          *int              `json:"scene,omitempty"`
+	Scenes         []*gltfScene      `json:"scenes,omitempty"`
+	Nodes          []*gltfNode       `json:"nodes,omitempty"`
+	Meshes         []*gltfMesh       `json:"meshes,omitempty"`
+	Materials      []*gltfMaterial   `json:"materials,omitempty"`
+	Cameras        []*gltfCamera     `json:"cameras,omitempty"`
+	Accessors      []*gltfAccessor   `json:"accessors,omitempty"`
+	BufferViews    []*gltfBufferView `json:"bufferViews,omitempty"`
+	Buffers        []*gltfBuffer     `json:"buffers,omitempty"`
+	ExtensionsUsed []string          `json:"extensionsUsed,omitempty"`
+	Extensions     *gltfExtensions   `json:"extensions,omitempty"`
+}
+
+type gltfExtensions struct {
+	LightsPunctual *gltfLights `json:"KHR_lights_punctual,omitempty"`
+}
+
+type gltfLights struct {
+	Lights []*gltfLight `json:"lights"`
+}
+
+type gltfLight struct {
+	Name      string    `json:"name,omitempty"`
+	Type      string    `json:"type"`
+	Color     []float64 `json:"color,omitempty"`
+	Intensity *float64  `json:"intensity,omitempty"`
+	Range     *float64  `json:"range,omitempty"`
+	Spot      *gltfSpot `json:"spot,omitempty"`
+}
+
+type gltfSpot struct {
+	InnerConeAngle float64  `json:"innerConeAngle,omitempty"`
+	OuterConeAngle *float64 `json:"outerConeAngle,omitempty"`
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator,omitempty"`
+	Copyright string `json:"copyright,omitempty"`
+}
+
+type gltfScene struct {
+	Name  string `json:"name,omitempty"`
+	Nodes []int  `json:"nodes,omitempty"`
+}
+
+type gltfNode struct {
+	Name        string    `json:"name,omitempty"`
+	Children    []int     `json:"children,omitempty"`
+	Matrix      []float64 `json:"matrix,omitempty"`
+	Translation []float64 `json:"translation,omitempty"`
+	Rotation    []float64 `json:"rotation,omitempty"`
+	Scale       []float64 `json:"scale,omitempty"`
+	Mesh        *int      `json:"mesh,omitempty"`
+	Camera      *int      `json:"camera,omitempty"`
+	Extensions  *struct {
+		LightsPunctual *struct {
+			Light int `json:"light"`
+		} `json:"KHR_lights_punctual,omitempty"`
+	} `json:"extensions,omitempty"`
+}
+
+type gltfMesh struct {
+	Name       string           `json:"name,omitempty"`
+	Primitives []*gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices,omitempty"`
+	Material   *int           `json:"material,omitempty"`
+	Mode       *int           `json:"mode,omitempty"`
+}
+
+type gltfMaterial struct {
+	Name                 string    `json:"name,omitempty"`
+	PbrMetallicRoughness *gltfPbr  `json:"pbrMetallicRoughness,omitempty"`
+	EmissiveFactor       []float64 `json:"emissiveFactor,omitempty"`
+	AlphaMode            string    `json:"alphaMode,omitempty"`
+	DoubleSided          bool      `json:"doubleSided,omitempty"`
+}
+
+type gltfPbr struct {
+	BaseColorFactor []float64 `json:"baseColorFactor,omitempty"`
+	MetallicFactor  *float64  `json:"metallicFactor,omitempty"`
+	RoughnessFactor *float64  `json:"roughnessFactor,omitempty"`
+}
+
+type gltfCamera struct {
+	Name         string            `json:"name,omitempty"`
+	Type         string            `json:"type"`
+	Perspective  *gltfPerspective  `json:"perspective,omitempty"`
+	Orthographic *gltfOrthographic `json:"orthographic,omitempty"`
+}
+
+type gltfPerspective struct {
+	AspectRatio float64  `json:"aspectRatio,omitempty"`
+	Yfov        float64  `json:"yfov"`
+	Znear       float64  `json:"znear"`
+	Zfar        *float64 `json:"zfar,omitempty"`
+}
+
+type gltfOrthographic struct {
+	Xmag  float64 `json:"xmag"`
+	Ymag  float64 `json:"ymag"`
+	Znear float64 `json:"znear"`
+	Zfar  float64 `json:"zfar"`
+}
+
+type gltfAccessor struct {
+	BufferView    *int      `json:"bufferView,omitempty"`
+	ByteOffset    int       `json:"byteOffset,omitempty"`
+	ComponentType int       `json:"componentType"`
+	Normalized    bool      `json:"normalized,omitempty"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Max           []float64 `json:"max,omitempty"`
+	Min           []float64 `json:"min,omitempty"`
+	Sparse        *struct {
+		Count   int `json:"count"`
+		Indices struct {
+			BufferView    int `json:"bufferView"`
+			ByteOffset    int `json:"byteOffset,omitempty"`
+			ComponentType int `json:"componentType"`
+		} `json:"indices"`
+		Values struct {
+			BufferView int `json:"bufferView"`
+			ByteOffset int `json:"byteOffset,omitempty"`
+		} `json:"values"`
+	} `json:"sparse,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset,omitempty"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride,omitempty"`
+	Target     int `json:"target,omitempty"`
+}
+
+type gltfBuffer struct {
+	ByteLength int    `json:"byteLength"`
+	Uri        string `json:"uri,omitempty"`
+}
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func floatPointer(f float64) *float64 {
+	return &f
+}
+
+//gltfTypes names the accessor type of elements with the given number of components.
+var gltfTypes = map[int]string{1: "SCALAR", 2: "VEC2", 3: "VEC3", 4: "VEC4", 16: "MAT4"}
+
+//gltfSizes gives the number of components of each accessor type.
+var gltfSizes = map[string]int{"SCALAR": 1, "VEC2": 2, "VEC3": 3, "VEC4": 4, "MAT2": 4, "MAT3": 9, "MAT4": 16}
+
+//gltfWriter converts a document to glTF, sharing meshes, materials and cameras between the nodes that use them.
+type gltfWriter struct {
+	collada   *Collada
+	gltf      *gltfDocument
+	bin       bytes.Buffer
+	meshes    map[string]int
+	materials map[*Material]int
+	cameras   map[*Camera]int
+}
+
+//ExportGltf writes the default visual scene as glTF 2.0 JSON to filename,
+//with its binary data in a .bin file of the same name.
+func (collada *Collada) ExportGltf(filename string) error {
+	binFilename := strings.TrimSuffix(filename, filepath.Ext(filename)) + ".bin"
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	bin, err := os.Create(binFilename)
+	if err != nil {
+		return err
+	}
+	defer bin.Close()
+	return collada.ExportGltfToWriter(file, bin, filepath.Base(binFilename))
+}
+
+//ExportGltfToWriter writes the default visual scene as glTF 2.0 JSON to writer and its binary buffer to bin.
+//The JSON refers to the buffer by binUri.
+func (collada *Collada) ExportGltfToWriter(writer, bin io.Writer, binUri string) error {
+	g, err := collada.gltf()
+	if err != nil {
+		return err
+	}
+	if len(g.gltf.Buffers) > 0 {
+		g.gltf.Buffers[0].Uri = binUri
+	}
+	if _, err := bin.Write(g.bin.Bytes()); err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", " ")
+	return encoder.Encode(g.gltf)
+}
+
+//ExportGlb writes the default visual scene to filename as binary glTF 2.0.
+func (collada *Collada) ExportGlb(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return collada.ExportGlbToWriter(file)
+}
+
+//ExportGlbToWriter writes the default visual scene as binary glTF 2.0.
+func (collada *Collada) ExportGlbToWriter(writer io.Writer) error {
+	g, err := collada.gltf()
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(g.gltf)
+	if err != nil {
+		return err
+	}
+	for len(content)%4 != 0 {
+		content = append(content, ' ')
+	}
+	bin := g.bin.Bytes()
+	length := 12 + 8 + len(content)
+	if len(bin) > 0 {
+		length += 8 + len(bin)
+	}
+	header := []uint32{glbMagic, 2, uint32(length), uint32(len(content)), glbChunkJson}
+	if err := binary.Write(writer, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	if _, err := writer.Write(content); err != nil {
+		return err
+	}
+	if len(bin) == 0 {
+		return nil
+	}
+	if err := binary.Write(writer, binary.LittleEndian, []uint32{uint32(len(bin)), glbChunkBin}); err != nil {
+		return err
+	}
+	_, err = writer.Write(bin)
+	return err
+}
+
+//gltf converts the default visual scene.
+func (collada *Collada) gltf() (*gltfWriter, error) {
+	scene := collada.DefaultVisualScene()
+	if scene == nil {
+		return nil, fmt.Errorf("collada: document has no visual scene")
+	}
+	g := &gltfWriter{
+		collada:   collada,
+		gltf:      &gltfDocument{Asset: gltfAsset{Version: "2.0", Generator: "go-collada"}},
+		meshes:    make(map[string]int),
+		materials: make(map[*Material]int),
+		cameras:   make(map[*Camera]int),
+	}
+	roots := []int{}
+	for _, node := range scene.Node {
+		index, err := g.node(node, make(map[*Node]bool))
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, index)
+	}
+	//glTF is Y up and measured in meters, so the document axes are corrected by a root node.
+	if correction := collada.gltfCorrection(); correction != Identity() {
+		g.gltf.Nodes = append(g.gltf.Nodes, &gltfNode{Name: "root", Children: roots, Matrix: correction.gltfMatrix()})
+		roots = []int{len(g.gltf.Nodes) - 1}
+	}
+	g.gltf.Scenes = []*gltfScene{{Name: scene.Name, Nodes: roots}}
+	g.gltf.Scene = intPointer(0)
+	g.align()
+	if g.bin.Len() > 0 {
+		g.gltf.Buffers = []*gltfBuffer{{ByteLength: g.bin.Len()}}
+	}
+	return g, nil
+}
+
+//gltfCorrection returns the transform from the document's axes and units to Y up meters.
+func (collada *Collada) gltfCorrection() Mat4 {
+	m := Identity()
+	if collada.Asset == nil {
+		return m
+	}
+	switch collada.Asset.UpAxis {
+	case Zup:
+		m = Rotation(1, 0, 0, -90)
+	case Xup:
+		m = Rotation(0, 0, 1, 90)
+	}
+	if unit := collada.Asset.Unit; unit != nil && unit.Meter > 0 && unit.Meter != 1 {
+		m = Scaling(unit.Meter, unit.Meter, unit.Meter).Mul(m)
+	}
+	return m
+}
+
+//gltfMatrix returns the matrix in the column-major order used by glTF.
+func (m Mat4) gltfMatrix() []float64 {
+	t := m.Transpose()
+	return t[:]
+}
+
+//node converts a node and its children, duplicating nodes which are instanced more than once.
+func (g *gltfWriter) node(node *Node, path map[*Node]bool) (int, error) {
+	n := &gltfNode{Name: node.Name}
+	if n.Name == "" {
+		n.Name = string(node.Id)
+	}
+	if local := node.LocalTransform(); local != Identity() {
+		n.Matrix = local.gltfMatrix()
+	}
+	if len(node.InstanceGeometry) > 0 {
+		mesh, err := g.mesh(node.InstanceGeometry)
+		if err != nil {
+			return 0, err
+		}
+		n.Mesh = mesh
+	}
+	for _, instance := range node.InstanceCamera {
+		if id, ok := instance.Url.Id(); ok {
+			if camera := g.collada.FindCamera(id); camera != nil {
+				n.Camera = g.camera(camera)
+				break
+			}
+		}
+	}
+	index := len(g.gltf.Nodes)
+	g.gltf.Nodes = append(g.gltf.Nodes, n)
+	path[node] = true
+	defer delete(path, node)
+	children := node.Node
+	for _, instance := range node.InstanceNode {
+		if id, ok := instance.Url.Id(); ok {
+			if instanced := g.collada.FindNode(id); instanced != nil && !path[instanced] {
+				children = append(children, instanced)
+			}
+		}
+	}
+	for _, child := range children {
+		if path[child] {
+			continue
+		}
+		c, err := g.node(child, path)
+		if err != nil {
+			return 0, err
+		}
+		n.Children = append(n.Children, c)
+	}
+	return index, nil
+}
+
+//mesh converts the geometries instanced by a node into a single mesh, returning nil if none have polygons.
+func (g *gltfWriter) mesh(instances []*InstanceGeometry) (*int, error) {
+	key := []string{}
+	for _, instance := range instances {
+		key = append(key, string(instance.Url))
+		if instance.BindMaterial != nil {
+			for _, bound := range instance.BindMaterial.TechniqueCommon.InstanceMaterial {
+				key = append(key, bound.Symbol+"="+string(bound.Target))
+			}
+		}
+		key = append(key, ";")
+	}
+	if index, ok := g.meshes[strings.Join(key, " ")]; ok {
+		return intPointer(index), nil
+	}
+	mesh := &gltfMesh{}
+	for _, instance := range instances {
+		id, ok := instance.Url.Id()
+		if !ok {
+			continue
+		}
+		geometry := g.collada.FindGeometry(id)
+		if geometry == nil || geometry.Mesh == nil {
+			continue
+		}
+		if mesh.Name == "" {
+			mesh.Name = geometry.Name
+		}
+		primitives, err := geometry.Mesh.Primitives()
+		if err != nil {
+			return nil, err
+		}
+		materials := g.collada.BoundMaterials(instance.BindMaterial)
+		for _, primitive := range primitives {
+			p := g.primitive(primitive)
+			if p == nil {
+				continue
+			}
+			if material, ok := materials[primitive.Material]; ok {
+				p.Material = intPointer(g.material(material))
+			}
+			mesh.Primitives = append(mesh.Primitives, p)
+		}
+	}
+	if len(mesh.Primitives) == 0 {
+		return nil, nil
+	}
+	index := len(g.gltf.Meshes)
+	g.gltf.Meshes = append(g.gltf.Meshes, mesh)
+	g.meshes[strings.Join(key, " ")] = index
+	return intPointer(index), nil
+}
+
+//primitive converts the triangles of a primitive, creating one glTF vertex for each distinct combination of attribute indices.
+func (g *gltfWriter) primitive(primitive *Primitive) *gltfPrimitive {
+	positions := primitive.Attribute("POSITION")
+	if positions == nil {
+		return nil
+	}
+	semantics := []string{"POSITION"}
+	attributes := []*Attribute{positions}
+	if normals := primitive.Attribute("NORMAL"); normals != nil {
+		semantics = append(semantics, "NORMAL")
+		attributes = append(attributes, normals)
+	}
+	texcoords := []*Attribute{}
+	for _, attribute := range primitive.Attributes {
+		if attribute.Semantic == "TEXCOORD" {
+			texcoords = append(texcoords, attribute)
+		}
+	}
+	for i, texcoord := range texcoords {
+		semantics = append(semantics, fmt.Sprintf("TEXCOORD_%d", i))
+		attributes = append(attributes, texcoord)
+	}
+	if colors := primitive.Attribute("COLOR"); colors != nil {
+		semantics = append(semantics, "COLOR_0")
+		attributes = append(attributes, colors)
+	}
+	sizes := map[string]int{"POSITION": 3, "NORMAL": 3, "COLOR_0": 4}
+	vertices := make(map[string]uint32)
+	data := make([][]float32, len(attributes))
+	indices := []uint32{}
+	for _, corner := range primitive.Triangles() {
+		key := make([]byte, 0, 8*len(attributes))
+		for _, attribute := range attributes {
+			key = binary.LittleEndian.AppendUint64(key, uint64(attribute.Indices[corner]))
+		}
+		index, ok := vertices[string(key)]
+		if !ok {
+			index = uint32(len(vertices))
+			vertices[string(key)] = index
+			for a, attribute := range attributes {
+				element := attribute.Element(corner)
+				size, ok := sizes[semantics[a]]
+				if !ok {
+					size = 2
+				}
+				for c := 0; c < size; c++ {
+					v := floatsAt(element, c)
+					switch {
+					case semantics[a] == "COLOR_0" && c == 3 && len(element) < 4:
+						v = 1
+					case size == 2 && c == 1:
+						v = 1 - v
+					}
+					data[a] = append(data[a], float32(v))
+				}
+			}
+		}
+		indices = append(indices, index)
+	}
+	if len(indices) == 0 {
+		return nil
+	}
+	p := &gltfPrimitive{Attributes: make(map[string]int)}
+	for a, semantic := range semantics {
+		size, ok := sizes[semantic]
+		if !ok {
+			size = 2
+		}
+		p.Attributes[semantic] = g.floatAccessor(data[a], size, semantic == "POSITION")
+	}
+	p.Indices = intPointer(g.indexAccessor(indices, len(vertices)))
+	return p
+}
+
+//align pads the binary buffer to a multiple of four bytes.
+func (g *gltfWriter) align() {
+	for g.bin.Len()%4 != 0 {
+		g.bin.WriteByte(0)
+	}
+}
+
+func (g *gltfWriter) bufferView(length, target int) int {
+	view := &gltfBufferView{ByteOffset: g.bin.Len(), ByteLength: length, Target: target}
+	g.gltf.BufferViews = append(g.gltf.BufferViews, view)
+	return len(g.gltf.BufferViews) - 1
+}
+
+func (g *gltfWriter) floatAccessor(data []float32, size int, bounds bool) int {
+	g.align()
+	accessor := &gltfAccessor{
+		BufferView:    intPointer(g.bufferView(len(data)*4, gltfArrayBuffer)),
+		ComponentType: gltfFloat,
+		Count:         len(data) / size,
+		Type:          gltfTypes[size],
+	}
+	if bounds {
+		accessor.Min = make([]float64, size)
+		accessor.Max = make([]float64, size)
+		for c := 0; c < size; c++ {
+			accessor.Min[c] = math.Inf(1)
+			accessor.Max[c] = math.Inf(-1)
+		}
+		for i, v := range data {
+			c := i % size
+			accessor.Min[c] = math.Min(accessor.Min[c], float64(v))
+			accessor.Max[c] = math.Max(accessor.Max[c], float64(v))
+		}
+	}
+	binary.Write(&g.bin, binary.LittleEndian, data)
+	g.gltf.Accessors = append(g.gltf.Accessors, accessor)
+	return len(g.gltf.Accessors) - 1
+}
+
+func (g *gltfWriter) indexAccessor(indices []uint32, vertices int) int {
+	g.align()
+	accessor := &gltfAccessor{Count: len(indices), Type: "SCALAR"}
+	if vertices <= math.MaxUint16 {
+		shorts := make([]uint16, len(indices))
+		for i, index := range indices {
+			shorts[i] = uint16(index)
+		}
+		accessor.ComponentType = gltfUnsignedShort
+		accessor.BufferView = intPointer(g.bufferView(len(shorts)*2, gltfElementArrayBuffer))
+		binary.Write(&g.bin, binary.LittleEndian, shorts)
+	} else {
+		accessor.ComponentType = gltfUnsignedInt
+		accessor.BufferView = intPointer(g.bufferView(len(indices)*4, gltfElementArrayBuffer))
+		binary.Write(&g.bin, binary.LittleEndian, indices)
+	}
+	g.gltf.Accessors = append(g.gltf.Accessors, accessor)
+	return len(g.gltf.Accessors) - 1
+}
+
+//material approximates the profile_COMMON shading of a material with PBR metallic-roughness.
+func (g *gltfWriter) material(material *Material) int {
+	if index, ok := g.materials[material]; ok {
+		return index
+	}
+	m := &gltfMaterial{Name: material.Name}
+	if m.Name == "" {
+		m.Name = string(material.Id)
+	}
+	pbr := &gltfPbr{MetallicFactor: floatPointer(0), RoughnessFactor: floatPointer(1)}
+	m.PbrMetallicRoughness = pbr
+	common := g.collada.CommonMaterial(material)
+	if common == nil {
+		common = &CommonMaterial{}
+	}
+	base := [4]float64{1, 1, 1, 1}
+	if rgba, ok := common.Diffuse.RGBA(); ok {
+		base = rgba
+	}
+	if alpha, ok := common.Opacity(); ok {
+		base[3] = alpha
+	}
+	if base[3] < 1 {
+		m.AlphaMode = "BLEND"
+	}
+	if base != [4]float64{1, 1, 1, 1} {
+		pbr.BaseColorFactor = base[:]
+	}
+	//A Phong exponent maps to roughness through the Beckmann distribution approximation.
+	if exponent, ok := common.Shininess.Float(); ok && exponent >= 0 {
+		pbr.RoughnessFactor = floatPointer(math.Sqrt(2 / (exponent + 2)))
+	}
+	if rgba, ok := common.Emission.RGBA(); ok && (rgba[0] != 0 || rgba[1] != 0 || rgba[2] != 0) {
+		m.EmissiveFactor = []float64{math.Min(rgba[0], 1), math.Min(rgba[1], 1), math.Min(rgba[2], 1)}
+	}
+	index := len(g.gltf.Materials)
+	g.gltf.Materials = append(g.gltf.Materials, m)
+	g.materials[material] = index
+	return index
+}
+
+//camera converts the optics of a camera, deriving missing fields of view from the aspect ratio.
+func (g *gltfWriter) camera(camera *Camera) *int {
+	if index, ok := g.cameras[camera]; ok {
+		return intPointer(index)
+	}
+	c := &gltfCamera{Name: camera.Name}
+	optics := camera.Optics.TechniqueCommon
+	switch {
+	case optics.Perspective != nil:
+		p := optics.Perspective
+		perspective := &gltfPerspective{Znear: p.Znear.Value}
+		if p.Zfar.Value > 0 {
+			perspective.Zfar = floatPointer(p.Zfar.Value)
+		}
+		radians := func(f *Float) float64 { return f.Value * math.Pi / 180 }
+		switch {
+		case p.Xfov != nil && p.Yfov != nil:
+			perspective.Yfov = radians(p.Yfov)
+			perspective.AspectRatio = math.Tan(radians(p.Xfov)/2) / math.Tan(perspective.Yfov/2)
+		case p.Yfov != nil:
+			perspective.Yfov = radians(p.Yfov)
+			if p.AspectRatio != nil {
+				perspective.AspectRatio = p.AspectRatio.Value
+			}
+		case p.Xfov != nil && p.AspectRatio != nil && p.AspectRatio.Value > 0:
+			perspective.AspectRatio = p.AspectRatio.Value
+			perspective.Yfov = 2 * math.Atan(math.Tan(radians(p.Xfov)/2)/p.AspectRatio.Value)
+		case p.Xfov != nil:
+			perspective.Yfov = radians(p.Xfov)
+		}
+		c.Type = "perspective"
+		c.Perspective = perspective
+	case optics.Orthographic != nil:
+		o := optics.Orthographic
+		orthographic := &gltfOrthographic{Znear: o.Znear.Value, Zfar: o.Zfar.Value}
+		aspect := 1.0
+		if o.AspectRatio != nil && o.AspectRatio.Value > 0 {
+			aspect = o.AspectRatio.Value
+		}
+		if o.Xmag != nil {
+			orthographic.Xmag = o.Xmag.Value
+			orthographic.Ymag = o.Xmag.Value / aspect
+		}
+		if o.Ymag != nil {
+			orthographic.Ymag = o.Ymag.Value
+			if o.Xmag == nil {
+				orthographic.Xmag = o.Ymag.Value * aspect
+			}
+		}
+		c.Type = "orthographic"
+		c.Orthographic = orthographic
+	default:
+		return nil
+	}
+	index := len(g.gltf.Cameras)
+	g.gltf.Cameras = append(g.gltf.Cameras, c)
+	g.cameras[camera] = index
+	return intPointer(index)
+}
+
+//gltfReader converts a glTF document to Collada, sharing the geometry of a mesh between the nodes that use it.
+type gltfReader struct {
+	gltf       *gltfDocument
+	buffers    [][]byte
+	doc        *Document
+	geometries []*Geometry
+	materials  []*Material
+	cameras    []*Camera
+	lights     []*Light
+}
+
+//LoadGltf reads a glTF 2.0 .gltf or .glb file, resolving external buffers relative to it.
+func LoadGltf(filename string) (*Collada, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	dir := filepath.Dir(filename)
+	return LoadGltfFromReader(file, func(uri string) (io.ReadCloser, error) {
+		path, err := url.PathUnescape(uri)
+		if err != nil {
+			return nil, err
+		}
+		return os.Open(filepath.Join(dir, filepath.FromSlash(path)))
+	})
+}
+
+//LoadGltfFromReader reads a glTF 2.0 document in JSON or binary form into a Collada document.
+//Buffers which are neither embedded nor data uris are read through open, which may be nil to reject them.
+func LoadGltfFromReader(reader io.Reader, open func(uri string) (io.ReadCloser, error)) (*Collada, error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var bin []byte
+	if len(content) >= 4 && binary.LittleEndian.Uint32(content) == glbMagic {
+		if content, bin, err = readGlbChunks(content); err != nil {
+			return nil, err
+		}
+	}
+	r := &gltfReader{gltf: &gltfDocument{}, doc: NewDocument()}
+	if err := json.Unmarshal(content, r.gltf); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(r.gltf.Asset.Version, "2.") {
+		return nil, fmt.Errorf("collada: unsupported glTF version %q", r.gltf.Asset.Version)
+	}
+	for i, buffer := range r.gltf.Buffers {
+		data, err := readGltfBuffer(buffer, bin, open)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < buffer.ByteLength {
+			return nil, fmt.Errorf("collada: glTF buffer %d has %d of %d bytes", i, len(data), buffer.ByteLength)
+		}
+		r.buffers = append(r.buffers, data)
+	}
+	if err := r.build(); err != nil {
+		return nil, err
+	}
+	return r.doc.Collada, nil
+}
+
+//readGlbChunks splits a binary glTF file into its JSON content and binary buffer.
+func readGlbChunks(glb []byte) ([]byte, []byte, error) {
+	if len(glb) < 12 || binary.LittleEndian.Uint32(glb[4:]) != 2 {
+		return nil, nil, fmt.Errorf("collada: unsupported glb header")
+	}
+	var content, bin []byte
+	for offset := 12; offset+8 <= len(glb); {
+		length := int(binary.LittleEndian.Uint32(glb[offset:]))
+		kind := binary.LittleEndian.Uint32(glb[offset+4:])
+		offset += 8
+		if length < 0 || offset+length > len(glb) {
+			return nil, nil, fmt.Errorf("collada: glb chunk of %d bytes is truncated", length)
+		}
+		switch {
+		case kind == glbChunkJson && content == nil:
+			content = glb[offset : offset+length]
+		case kind == glbChunkBin && bin == nil:
+			bin = glb[offset : offset+length]
+		}
+		offset += length
+	}
+	if content == nil {
+		return nil, nil, fmt.Errorf("collada: glb has no json chunk")
+	}
+	return content, bin, nil
+}
+
+//readGltfBuffer returns the data of a buffer stored in the glb binary chunk, a data uri or an external file.
+func readGltfBuffer(buffer *gltfBuffer, bin []byte, open func(uri string) (io.ReadCloser, error)) ([]byte, error) {
+	switch {
+	case buffer.Uri == "":
+		return bin, nil
+	case strings.HasPrefix(buffer.Uri, "data:"):
+		comma := strings.IndexByte(buffer.Uri, ',')
+		if comma < 0 || !strings.HasSuffix(buffer.Uri[:comma], ";base64") {
+			return nil, fmt.Errorf("collada: unsupported glTF data uri")
+		}
+		return base64.StdEncoding.DecodeString(buffer.Uri[comma+1:])
+	case open == nil:
+		return nil, fmt.Errorf("collada: cannot open glTF buffer %q", buffer.Uri)
+	}
+	file, err := open(buffer.Uri)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+//gltfMaxElements bounds the elements of an accessor, which are allocated before they are read.
+const gltfMaxElements = 1 << 28
+
+//accessor reads the elements of an accessor as floats, returning the values and the number of components per element.
+func (r *gltfReader) accessor(index int) ([]float64, int, error) {
+	if index < 0 || index >= len(r.gltf.Accessors) {
+		return nil, 0, fmt.Errorf("collada: glTF accessor %d out of range", index)
+	}
+	a := r.gltf.Accessors[index]
+	size, ok := gltfSizes[a.Type]
+	if !ok {
+		return nil, 0, fmt.Errorf("collada: unknown glTF accessor type %q", a.Type)
+	}
+	if a.Count < 0 || a.Count > gltfMaxElements {
+		return nil, 0, fmt.Errorf("collada: glTF accessor %d has %d elements", index, a.Count)
+	}
+	if a.Sparse != nil && (a.Sparse.Count < 0 || a.Sparse.Count > a.Count) {
+		return nil, 0, fmt.Errorf("collada: glTF accessor %d has %d sparse elements", index, a.Sparse.Count)
+	}
+	values := make([]float64, a.Count*size)
+	if a.BufferView != nil {
+		if err := r.read(*a.BufferView, a.ByteOffset, a.ComponentType, a.Normalized, size, values); err != nil {
+			return nil, 0, err
+		}
+	}
+	if sparse := a.Sparse; sparse != nil {
+		indices := make([]float64, sparse.Count)
+		if err := r.read(sparse.Indices.BufferView, sparse.Indices.ByteOffset, sparse.Indices.ComponentType, false, 1, indices); err != nil {
+			return nil, 0, err
+		}
+		replaced := make([]float64, sparse.Count*size)
+		if err := r.read(sparse.Values.BufferView, sparse.Values.ByteOffset, a.ComponentType, a.Normalized, size, replaced); err != nil {
+			return nil, 0, err
+		}
+		for i, element := range indices {
+			e := int(element)
+			if e < 0 || e >= a.Count {
+				return nil, 0, fmt.Errorf("collada: glTF sparse index %d out of range for %d elements", e, a.Count)
+			}
+			copy(values[e*size:(e+1)*size], replaced[i*size:(i+1)*size])
+		}
+	}
+	return values, size, nil
+}
+
+//read decodes len(values)/size elements of a buffer view, normalizing integer components if requested.
+func (r *gltfReader) read(view, offset, componentType int, normalized bool, size int, values []float64) error {
+	if view < 0 || view >= len(r.gltf.BufferViews) {
+		return fmt.Errorf("collada: glTF buffer view %d out of range", view)
+	}
+	v := r.gltf.BufferViews[view]
+	if v.Buffer < 0 || v.Buffer >= len(r.buffers) {
+		return fmt.Errorf("collada: glTF buffer %d out of range", v.Buffer)
+	}
+	var width int
+	var decode func([]byte) float64
+	switch componentType {
+	case gltfByte:
+		width, decode = 1, func(b []byte) float64 { return math.Max(float64(int8(b[0]))/127, -1) }
+	case gltfUnsignedByte:
+		width, decode = 1, func(b []byte) float64 { return float64(b[0]) / 255 }
+	case gltfShort:
+		width, decode = 2, func(b []byte) float64 { return math.Max(float64(int16(binary.LittleEndian.Uint16(b)))/32767, -1) }
+	case gltfUnsignedShort:
+		width, decode = 2, func(b []byte) float64 { return float64(binary.LittleEndian.Uint16(b)) / 65535 }
+	case gltfUnsignedInt:
+		width, decode = 4, func(b []byte) float64 { return float64(binary.LittleEndian.Uint32(b)) }
+	case gltfFloat:
+		width, decode = 4, func(b []byte) float64 { return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))) }
+	default:
+		return fmt.Errorf("collada: unknown glTF component type %d", componentType)
+	}
+	if !normalized && componentType != gltfFloat {
+		//Integer components are only scaled to [0, 1] or [-1, 1] when the accessor is normalized.
+		switch width {
+		case 1:
+			decode = func(b []byte) float64 {
+				if componentType == gltfByte {
+					return float64(int8(b[0]))
+				}
+				return float64(b[0])
+			}
+		case 2:
+			decode = func(b []byte) float64 {
+				if componentType == gltfShort {
+					return float64(int16(binary.LittleEndian.Uint16(b)))
+				}
+				return float64(binary.LittleEndian.Uint16(b))
+			}
+		}
+	}
+	if offset < 0 || v.ByteOffset < 0 || v.ByteLength < 0 {
+		return fmt.Errorf("collada: glTF buffer view %d has a negative offset or length", view)
+	}
+	stride := v.ByteStride
+	switch {
+	case stride == 0:
+		stride = width * size
+	case stride < width*size:
+		return fmt.Errorf("collada: glTF buffer view %d has a stride of %d bytes for elements of %d bytes", view, stride, width*size)
+	}
+	data := r.buffers[v.Buffer]
+	if v.ByteOffset > len(data) || v.ByteLength > len(data)-v.ByteOffset {
+		return fmt.Errorf("collada: glTF buffer view %d exceeds its buffer", view)
+	}
+	start := v.ByteOffset + offset
+	count := len(values) / size
+	//The last element must end within the view; the comparisons are arranged not to overflow.
+	if available := v.ByteLength - offset; count > 0 && (available < width*size || (available-width*size)/stride < count-1) {
+		return fmt.Errorf("collada: glTF accessor of %d elements exceeds buffer view %d", count, view)
+	}
+	for i := 0; i < count; i++ {
+		element := data[start+i*stride:]
+		for c := 0; c < size; c++ {
+			values[i*size+c] = decode(element[c*width:])
+		}
+	}
+	return nil
+}
+
+//build converts every scene, defaulting to the scene named by the document.
+func (r *gltfReader) build() error {
+	if asset := r.gltf.Asset; asset.Generator != "" || asset.Copyright != "" {
+		contributor := &Contributor{AuthoringTool: asset.Generator, Copyright: asset.Copyright}
+		r.doc.Collada.Asset.Contributor = append(r.doc.Collada.Asset.Contributor, contributor)
+	}
+	for _, m := range r.gltf.Materials {
+		r.materials = append(r.materials, r.material(m))
+	}
+	r.geometries = make([]*Geometry, len(r.gltf.Meshes))
+	if extensions := r.gltf.Extensions; extensions != nil && extensions.LightsPunctual != nil {
+		for _, light := range extensions.LightsPunctual.Lights {
+			r.lights = append(r.lights, r.light(light))
+		}
+	}
+	for _, camera := range r.gltf.Cameras {
+		r.cameras = append(r.cameras, r.camera(camera))
+	}
+	scenes := r.gltf.Scenes
+	if len(scenes) == 0 {
+		//Without scenes, every node which is not a child of another is a root.
+		child := make(map[int]bool)
+		for _, node := range r.gltf.Nodes {
+			for _, c := range node.Children {
+				child[c] = true
+			}
+		}
+		scene := &gltfScene{}
+		for i := range r.gltf.Nodes {
+			if !child[i] {
+				scene.Nodes = append(scene.Nodes, i)
+			}
+		}
+		scenes = []*gltfScene{scene}
+	}
+	main := r.doc.Scene
+	library := r.doc.Collada.LibraryVisualScenes[0]
+	for i, s := range scenes {
+		if i > 0 {
+			name := s.Name
+			if name == "" {
+				name = "Scene"
+			}
+			r.doc.Scene = &VisualScene{HasId: HasId{r.doc.newId(name)}, HasName: HasName{name}}
+			library.VisualScene = append(library.VisualScene, r.doc.Scene)
+		} else if s.Name != "" {
+			r.doc.Scene.Name = s.Name
+		}
+		for _, index := range s.Nodes {
+			if err := r.node(index, nil, make(map[int]bool)); err != nil {
+				return err
+			}
+		}
+	}
+	r.doc.Scene = main
+	if index := r.gltf.Scene; index != nil && *index > 0 && *index < len(scenes) {
+		r.doc.Scene = library.VisualScene[*index]
+		r.doc.Collada.Scene.InstanceVisualScene.Url = r.doc.Scene.Id.Uri()
+	}
+	return nil
+}
+
+//node converts a node and its children, placed beneath parent or at the root of the current scene.
+func (r *gltfReader) node(index int, parent *Node, path map[int]bool) error {
+	if index < 0 || index >= len(r.gltf.Nodes) {
+		return fmt.Errorf("collada: glTF node %d out of range", index)
+	}
+	if path[index] {
+		return fmt.Errorf("collada: glTF node %d is its own ancestor", index)
+	}
+	path[index] = true
+	defer delete(path, index)
+	n := r.gltf.Nodes[index]
+	name := n.Name
+	if name == "" {
+		name = "node"
+	}
+	node := r.doc.AddNode(name, parent)
+	if len(n.Matrix) == 16 {
+		var m Mat4
+		copy(m[:], n.Matrix)
+		if m = m.Transpose(); m != Identity() {
+			node.Matrix = append(node.Matrix, NewMatrix(m))
+		}
+	}
+	if t := n.Translation; len(t) == 3 && (t[0] != 0 || t[1] != 0 || t[2] != 0) {
+		node.Translate = append(node.Translate, NewTranslate(t[0], t[1], t[2]))
+	}
+	if q := n.Rotation; len(q) == 4 {
+		//The unit quaternion (x, y, z, w) rotates by 2 acos(w) around its normalized vector part.
+		w := math.Max(-1, math.Min(1, q[3]))
+		if s := math.Sqrt(1 - w*w); s > 1e-9 {
+			angle := 2 * math.Acos(w) * 180 / math.Pi
+			node.Rotate = append(node.Rotate, NewRotate(q[0]/s, q[1]/s, q[2]/s, angle))
+		}
+	}
+	if s := n.Scale; len(s) == 3 && (s[0] != 1 || s[1] != 1 || s[2] != 1) {
+		node.Scale = append(node.Scale, NewScale(s[0], s[1], s[2]))
+	}
+	if n.Mesh != nil {
+		geometry, err := r.geometry(*n.Mesh)
+		if err != nil {
+			return err
+		}
+		instance := &InstanceGeometry{HasUrl: HasUrl{geometry.Id.Uri()}}
+		bound := make(map[int]bool)
+		for _, p := range r.gltf.Meshes[*n.Mesh].Primitives {
+			if p.Material != nil && !bound[*p.Material] {
+				bound[*p.Material] = true
+				material := r.materials[*p.Material]
+				r.doc.Bind(instance, string(material.Id), material)
+			}
+		}
+		node.InstanceGeometry = append(node.InstanceGeometry, instance)
+	}
+	if n.Camera != nil {
+		if *n.Camera < 0 || *n.Camera >= len(r.cameras) {
+			return fmt.Errorf("collada: glTF camera %d out of range", *n.Camera)
+		}
+		instance := &InstanceCamera{HasUrl: HasUrl{r.cameras[*n.Camera].Id.Uri()}}
+		node.InstanceCamera = append(node.InstanceCamera, instance)
+	}
+	if n.Extensions != nil && n.Extensions.LightsPunctual != nil {
+		light := n.Extensions.LightsPunctual.Light
+		if light < 0 || light >= len(r.lights) {
+			return fmt.Errorf("collada: glTF light %d out of range", light)
+		}
+		instance := &InstanceLight{HasUrl: HasUrl{r.lights[light].Id.Uri()}}
+		node.InstanceLight = append(node.InstanceLight, instance)
+	}
+	for _, child := range n.Children {
+		if err := r.node(child, node, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//gltfSemantics maps the glTF vertex attributes which have a Collada equivalent to their semantic.
+var gltfSemantics = map[string]string{"POSITION": "POSITION", "NORMAL": "NORMAL", "TEXCOORD": "TEXCOORD", "COLOR": "COLOR"}
+
+//gltfInput is a vertex attribute of a mesh, gathered from every primitive into one source.
+type gltfInput struct {
+	attribute string
+	semantic  string
+	set       uint
+	size      int
+	values    []float64
+	source    *Source
+}
+
+//geometry converts a mesh, concatenating the vertices of its primitives into shared sources.
+//Each primitive indexes every source with a single offset, as vertex attributes are not indexed separately in glTF.
+func (r *gltfReader) geometry(index int) (*Geometry, error) {
+	if index < 0 || index >= len(r.gltf.Meshes) {
+		return nil, fmt.Errorf("collada: glTF mesh %d out of range", index)
+	}
+	if geometry := r.geometries[index]; geometry != nil {
+		return geometry, nil
+	}
+	m := r.gltf.Meshes[index]
+	name := m.Name
+	if name == "" {
+		name = "mesh"
+	}
+	id := r.doc.newId(name + "-mesh")
+	inputs := []*gltfInput{}
+	lookup := make(map[string]*gltfInput)
+	type converted struct {
+		attributes map[string]int
+		base       int
+		count      int
+		indices    []int
+		mode       int
+		material   string
+	}
+	primitives := []converted{}
+	vertices := 0
+	for _, p := range m.Primitives {
+		//Materials are checked for every primitive, as the nodes instancing the mesh bind them all.
+		if p.Material != nil && (*p.Material < 0 || *p.Material >= len(r.materials)) {
+			return nil, fmt.Errorf("collada: glTF material %d out of range", *p.Material)
+		}
+		position, ok := p.Attributes["POSITION"]
+		if !ok {
+			continue
+		}
+		if position < 0 || position >= len(r.gltf.Accessors) {
+			return nil, fmt.Errorf("collada: glTF accessor %d out of range", position)
+		}
+		count := r.gltf.Accessors[position].Count
+		for attribute, accessor := range p.Attributes {
+			semantic, set := attribute, uint(0)
+			if underscore := strings.LastIndexByte(attribute, '_'); underscore > 0 {
+				if n, err := strconv.Atoi(attribute[underscore+1:]); err == nil {
+					semantic, set = attribute[:underscore], uint(n)
+				}
+			}
+			semantic, ok := gltfSemantics[semantic]
+			if !ok {
+				continue
+			}
+			values, size, err := r.accessor(accessor)
+			if err != nil {
+				return nil, err
+			}
+			if len(values) != count*size {
+				return nil, fmt.Errorf("collada: glTF attribute %s has %d of %d vertices", attribute, len(values)/size, count)
+			}
+			if semantic == "TEXCOORD" {
+				//glTF places the origin of texture space at the top left rather than the bottom left.
+				for i := 1; i < len(values); i += size {
+					values[i] = 1 - values[i]
+				}
+			}
+			if semantic == "COLOR" && size == 3 {
+				rgba := make([]float64, 0, count*4)
+				for i := 0; i < len(values); i += 3 {
+					rgba = append(rgba, values[i], values[i+1], values[i+2], 1)
+				}
+				values, size = rgba, 4
+			}
+			input, ok := lookup[attribute]
+			if !ok {
+				input = &gltfInput{attribute: attribute, semantic: semantic, set: set, size: size}
+				lookup[attribute] = input
+				inputs = append(inputs, input)
+			}
+			if input.size != size {
+				return nil, fmt.Errorf("collada: glTF attribute %s has %d and %d components", attribute, input.size, size)
+			}
+			//Primitives without the attribute leave zeros in its source which they do not index.
+			input.values = append(input.values, make([]float64, vertices*size-len(input.values))...)
+			input.values = append(input.values, values...)
+		}
+		c := converted{attributes: p.Attributes, base: vertices, count: count, mode: gltfTriangles}
+		if p.Mode != nil {
+			c.mode = *p.Mode
+		}
+		if p.Indices != nil {
+			values, _, err := r.accessor(*p.Indices)
+			if err != nil {
+				return nil, err
+			}
+			c.indices = make([]int, len(values))
+			for i, v := range values {
+				if v < 0 || int(v) >= count {
+					return nil, fmt.Errorf("collada: glTF index %d out of range for %d vertices", int(v), count)
+				}
+				c.indices[i] = int(v)
+			}
+		} else {
+			c.indices = make([]int, count)
+			for i := range c.indices {
+				c.indices[i] = i
+			}
+		}
+		if p.Material != nil {
+			c.material = string(r.materials[*p.Material].Id)
+		}
+		primitives = append(primitives, c)
+		vertices += count
+	}
+	sort.SliceStable(inputs, func(i, j int) bool {
+		order := map[string]int{"POSITION": 0, "NORMAL": 1, "TEXCOORD": 2, "COLOR": 3}
+		a, b := inputs[i], inputs[j]
+		if a.semantic != b.semantic {
+			return order[a.semantic] < order[b.semantic]
+		}
+		return a.set < b.set
+	})
+	mesh := &Mesh{}
+	mesh.Vertices = Vertices{HasId: HasId{r.doc.newId(string(id) + "-vertices")}}
+	for _, input := range inputs {
+		input.values = append(input.values, make([]float64, vertices*input.size-len(input.values))...)
+		var suffix string
+		var params []string
+		switch input.semantic {
+		case "POSITION":
+			suffix, params = "positions", []string{"X", "Y", "Z"}
+		case "NORMAL":
+			suffix, params = "normals", []string{"X", "Y", "Z"}
+		case "TEXCOORD":
+			suffix, params = "map-"+strconv.Itoa(int(input.set)), []string{"S", "T", "P"}[:input.size]
+		case "COLOR":
+			suffix, params = "colors-"+strconv.Itoa(int(input.set)), []string{"R", "G", "B", "A"}
+		}
+		if len(params) != input.size {
+			return nil, fmt.Errorf("collada: glTF attribute %s has %d components", input.attribute, input.size)
+		}
+		input.source = r.doc.newSource(string(id)+"-"+suffix, input.values, params...)
+		mesh.Source = append(mesh.Source, input.source)
+		if input.semantic == "POSITION" {
+			mesh.Vertices.Input = append(mesh.Vertices.Input, &InputUnshared{Semantic: "POSITION", Source: input.source.Id.Uri()})
+		}
+	}
+	for _, c := range primitives {
+		shared := []*InputShared{{Semantic: "VERTEX", Source: mesh.Vertices.Id.Uri()}}
+		for _, input := range inputs {
+			if _, ok := c.attributes[input.attribute]; ok && input.semantic != "POSITION" {
+				shared = append(shared, &InputShared{Semantic: input.semantic, Source: input.source.Id.Uri(), Set: input.set})
+			}
+		}
+		p := make(IntValues, len(c.indices))
+		for j, index := range c.indices {
+			p[j] = int32(c.base + index)
+		}
+		switch c.mode {
+		case gltfTriangles, gltfTriangleStrip, gltfTriangleFan:
+			p = gltfTriangleList(p, c.mode)
+			mesh.Triangles = append(mesh.Triangles, &Triangles{
+				HasCount:       HasCount{len(p) / 3},
+				HasMaterial:    HasMaterial{c.material},
+				HasSharedInput: HasSharedInput{shared},
+				HasP:           HasP{&P{Ints{p}}},
+			})
+		case gltfLines, gltfLineStrip, gltfLineLoop:
+			p = gltfLineList(p, c.mode)
+			mesh.Lines = append(mesh.Lines, &Lines{
+				HasCount:       HasCount{len(p) / 2},
+				HasMaterial:    HasMaterial{c.material},
+				HasSharedInput: HasSharedInput{shared},
+				HasP:           HasP{&P{Ints{p}}},
+			})
+		}
+	}
+	geometry := &Geometry{HasId: HasId{id}, HasName: HasName{m.Name}, Mesh: mesh}
+	library := r.doc.Collada.LibraryGeometries[0]
+	library.Geometry = append(library.Geometry, geometry)
+	r.geometries[index] = geometry
+	return geometry, nil
+}
+
+//gltfTriangleList expands triangle strips and fans into independent triangles.
+func gltfTriangleList(p IntValues, mode int) IntValues {
+	switch mode {
+	case gltfTriangleStrip:
+		triangles := IntValues{}
+		for i := 2; i < len(p); i++ {
+			if i%2 == 0 {
+				triangles = append(triangles, p[i-2], p[i-1], p[i])
+			} else {
+				triangles = append(triangles, p[i-1], p[i-2], p[i])
+			}
+		}
+		return triangles
+	case gltfTriangleFan:
+		triangles := IntValues{}
+		for i := 2; i < len(p); i++ {
+			triangles = append(triangles, p[0], p[i-1], p[i])
+		}
+		return triangles
+	}
+	return p[:len(p)-len(p)%3]
+}
+
+//gltfLineList expands line strips and loops into independent segments.
+func gltfLineList(p IntValues, mode int) IntValues {
+	if mode == gltfLines {
+		return p[:len(p)-len(p)%2]
+	}
+	lines := IntValues{}
+	for i := 1; i < len(p); i++ {
+		lines = append(lines, p[i-1], p[i])
+	}
+	if mode == gltfLineLoop && len(p) > 2 {
+		lines = append(lines, p[len(p)-1], p[0])
+	}
+	return lines
+}
+
+//material converts a metallic-roughness material to phong, inverting the conversion used by the exporter.
+func (r *gltfReader) material(m *gltfMaterial) *Material {
+	name := m.Name
+	if name == "" {
+		name = "material"
+	}
+	base := [4]float64{1, 1, 1, 1}
+	metallic, roughness := 1.0, 1.0
+	if pbr := m.PbrMetallicRoughness; pbr != nil {
+		if len(pbr.BaseColorFactor) == 4 {
+			copy(base[:], pbr.BaseColorFactor)
+		}
+		if pbr.MetallicFactor != nil {
+			metallic = *pbr.MetallicFactor
+		}
+		if pbr.RoughnessFactor != nil {
+			roughness = *pbr.RoughnessFactor
+		}
+	}
+	//Dielectrics reflect 4% of light at normal incidence while metals tint their reflection by the base color.
+	specular := [4]float64{1, 1, 1, 1}
+	for i := 0; i < 3; i++ {
+		specular[i] = 0.04 + (base[i]-0.04)*metallic
+	}
+	roughness = math.Max(roughness, 0.01)
+	phong := &Phong{
+		Diffuse:   NewColor("diffuse", [4]float64{base[0], base[1], base[2], 1}),
+		Specular:  NewColor("specular", specular),
+		Shininess: NewFloat("shininess", 2/(roughness*roughness)-2),
+	}
+	if e := m.EmissiveFactor; len(e) == 3 && (e[0] != 0 || e[1] != 0 || e[2] != 0) {
+		phong.Emission = NewColor("emission", [4]float64{e[0], e[1], e[2], 1})
+	}
+	if m.AlphaMode == "BLEND" && base[3] < 1 {
+		phong.Transparent = NewColor("transparent", [4]float64{1, 1, 1, 1})
+		phong.Transparency = NewFloat("transparency", base[3])
+	}
+	return r.doc.AddPhongMaterial(name, phong)
+}
+
+//camera converts a camera; glTF and Collada cameras both look down their negative z axis.
+func (r *gltfReader) camera(c *gltfCamera) *Camera {
+	name := c.Name
+	if name == "" {
+		name = "camera"
+	}
+	camera := &Camera{HasId: HasId{r.doc.newId(name + "-camera")}, HasName: HasName{c.Name}}
+	optics := &camera.Optics.TechniqueCommon
+	switch {
+	case c.Perspective != nil:
+		p := c.Perspective
+		perspective := &Perspective{
+			Yfov:  &Float{Value: p.Yfov * 180 / math.Pi},
+			Znear: Float{Value: p.Znear},
+		}
+		if p.AspectRatio > 0 {
+			perspective.AspectRatio = &Float{Value: p.AspectRatio}
+		}
+		//Collada has no infinite projection, so the far plane is placed far beyond the near plane.
+		perspective.Zfar.Value = p.Znear * 1e6
+		if p.Zfar != nil {
+			perspective.Zfar.Value = *p.Zfar
+		}
+		optics.Perspective = perspective
+	case c.Orthographic != nil:
+		o := c.Orthographic
+		optics.Orthographic = &Orthographic{
+			Xmag:  &Float{Value: o.Xmag},
+			Ymag:  &Float{Value: o.Ymag},
+			Znear: Float{Value: o.Znear},
+			Zfar:  Float{Value: o.Zfar},
+		}
+	}
+	collada := r.doc.Collada
+	if len(collada.LibraryCameras) == 0 {
+		collada.LibraryCameras = []*LibraryCameras{{}}
+	}
+	collada.LibraryCameras[0].Camera = append(collada.LibraryCameras[0].Camera, camera)
+	return camera
+}
+
+//light converts a KHR_lights_punctual light, folding its intensity into its color.
+//Point and spot lights fall off with the inverse square of distance; their range is not representable.
+func (r *gltfReader) light(l *gltfLight) *Light {
+	name := l.Name
+	if name == "" {
+		name = l.Type
+	}
+	rgb := []float64{1, 1, 1}
+	if len(l.Color) == 3 {
+		rgb = append([]float64(nil), l.Color...)
+	}
+	if l.Intensity != nil {
+		for i := range rgb {
+			rgb[i] *= *l.Intensity
+		}
+	}
+	color := Color{HasSid{"color"}, Float3{Floats{rgb}}}
+	light := &Light{HasId: HasId{r.doc.newId(name + "-light")}, HasName: HasName{l.Name}}
+	common := &light.TechniqueCommon
+	switch l.Type {
+	case "directional":
+		common.Directional = &Directional{Color: color}
+	case "point":
+		common.Point = &Point{
+			Color:                color,
+			ConstantAttenuation:  &Float{HasSid{"constant_attenuation"}, 0},
+			LinearAttenuation:    &Float{HasSid{"linear_attenuation"}, 0},
+			QuadraticAttenuation: &Float{HasSid{"quadratic_attenuation"}, 1},
+		}
+	case "spot":
+		//Collada spreads a spot light over the full falloff angle rather than the half angle of the outer cone.
+		outer := math.Pi / 4
+		if l.Spot != nil && l.Spot.OuterConeAngle != nil {
+			outer = *l.Spot.OuterConeAngle
+		}
+		common.Spot = &Spot{
+			Color:                color,
+			ConstantAttenuation:  &Float{HasSid{"constant_attenuation"}, 0},
+			LinearAttenuation:    &Float{HasSid{"linear_attenuation"}, 0},
+			QuadraticAttenuation: &Float{HasSid{"quadratic_attenuation"}, 1},
+			FalloffAngle:         &Float{HasSid{"falloff_angle"}, 2 * outer * 180 / math.Pi},
+			FalloffExponent:      &Float{HasSid{"falloff_exponent"}, 0},
+		}
+	}
+	collada := r.doc.Collada
+	if len(collada.LibraryLights) == 0 {
+		collada.LibraryLights = []*LibraryLights{{}}
+	}
+	collada.LibraryLights[0].Light = append(collada.LibraryLights[0].Light, light)
+	return light
+}
@@ -0,0 +1,239 @@
+package collada
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//readGlb splits a binary glTF into its decoded JSON and binary chunk
+func readGlb(data []byte, t *testing.T) (*gltfDocument, []byte) {
+	if binary.LittleEndian.Uint32(data) != glbMagic || int(binary.LittleEndian.Uint32(data[8:])) != len(data) {
+		t.Fatal("wrong glb header")
+	}
+	length := int(binary.LittleEndian.Uint32(data[12:]))
+	if length%4 != 0 || binary.LittleEndian.Uint32(data[16:]) != glbChunkJson {
+		t.Fatal("wrong json chunk")
+	}
+	gltf := &gltfDocument{}
+	if err := json.Unmarshal(data[20:20+length], gltf); err != nil {
+		t.Fatal(err)
+	}
+	bin := data[28+length:]
+	if len(bin)%4 != 0 || int(binary.LittleEndian.Uint32(data[20+length:])) != len(bin) {
+		t.Fatal("wrong binary chunk")
+	}
+	return gltf, bin
+}
+
+func TestExportGlb(t *testing.T) {
+	collada, err := LoadDocument("cube.dae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := &bytes.Buffer{}
+	if err := collada.ExportGlbToWriter(buffer); err != nil {
+		t.Fatal(err)
+	}
+	gltf, bin := readGlb(buffer.Bytes(), t)
+	if len(gltf.Nodes) != 4 || gltf.Nodes[3].Name != "root" || len(gltf.Scenes[0].Nodes) != 1 {
+		t.Fatal("wrong node hierarchy", len(gltf.Nodes))
+	}
+	if len(gltf.Meshes) != 1 || len(gltf.Meshes[0].Primitives) != 1 {
+		t.Fatal("wrong meshes")
+	}
+	primitive := gltf.Meshes[0].Primitives[0]
+	if positions := gltf.Accessors[primitive.Attributes["POSITION"]]; positions.Count != 24 || positions.Max[0] != 1 {
+		t.Error("wrong positions", positions.Count, positions.Max)
+	}
+	if indices := gltf.Accessors[*primitive.Indices]; indices.Count != 36 || indices.ComponentType != gltfUnsignedShort {
+		t.Error("wrong indices", indices.Count)
+	}
+	view := gltf.BufferViews[*gltf.Accessors[*primitive.Indices].BufferView]
+	if view.ByteOffset+view.ByteLength > len(bin) {
+		t.Error("buffer view outside binary chunk")
+	}
+	material := gltf.Materials[*primitive.Material]
+	if material.Name != "Material" || material.PbrMetallicRoughness.BaseColorFactor[0] != 0.64 {
+		t.Error("wrong material", material)
+	}
+	if roughness := *material.PbrMetallicRoughness.RoughnessFactor; math.Abs(roughness-math.Sqrt(2.0/52)) > 1e-9 {
+		t.Error("wrong roughness", roughness)
+	}
+	camera := gltf.Cameras[*gltf.Nodes[0].Camera].Perspective
+	if math.Abs(camera.AspectRatio-1.777778) > 1e-9 || math.Abs(camera.Yfov-0.5033799) > 1e-6 {
+		t.Error("wrong camera", camera.AspectRatio, camera.Yfov)
+	}
+}
+
+func TestExportGltf(t *testing.T) {
+	collada, err := LoadDocument("screw.dae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := &bytes.Buffer{}
+	bin := &bytes.Buffer{}
+	if err := collada.ExportGltfToWriter(document, bin, "screw.bin"); err != nil {
+		t.Fatal(err)
+	}
+	gltf := &gltfDocument{}
+	if err := json.Unmarshal(document.Bytes(), gltf); err != nil {
+		t.Fatal(err)
+	}
+	if gltf.Buffers[0].Uri != "screw.bin" || gltf.Buffers[0].ByteLength != bin.Len() {
+		t.Error("wrong buffer", gltf.Buffers[0])
+	}
+}
+
+func TestLoadGlb(t *testing.T) {
+	collada, err := LoadDocument("cube.dae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	glb := &bytes.Buffer{}
+	if err := collada.ExportGlbToWriter(glb); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := LoadGltfFromReader(glb, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dae := &bytes.Buffer{}
+	if err := imported.ExportToWriter(dae); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadDocumentFromReader(dae)
+	if err != nil {
+		t.Fatal(err)
+	}
+	primitives, err := reloaded.LibraryGeometries[0].Geometry[0].Mesh.Primitives()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primitives) != 1 || len(primitives[0].Triangles()) != 36 {
+		t.Fatal("wrong triangles", len(primitives))
+	}
+	root := reloaded.FindNode("root")
+	if root == nil || len(root.Node) != 3 {
+		t.Fatal("wrong node hierarchy")
+	}
+	material := reloaded.LibraryMaterials[0].Material[0]
+	if diffuse, _ := reloaded.CommonMaterial(material).Diffuse.RGBA(); math.Abs(diffuse[0]-0.64) > 1e-6 {
+		t.Error("wrong diffuse", diffuse)
+	}
+	if shininess, _ := reloaded.CommonMaterial(material).Shininess.Float(); math.Abs(shininess-50) > 1e-6 {
+		t.Error("wrong shininess", shininess)
+	}
+	perspective := reloaded.LibraryCameras[0].Camera[0].Optics.TechniqueCommon.Perspective
+	if math.Abs(perspective.Yfov.Value-0.5033799*180/math.Pi) > 1e-4 || perspective.Zfar.Value != 100 {
+		t.Error("wrong camera", perspective.Yfov.Value, perspective.Zfar.Value)
+	}
+}
+
+func TestLoadGltf(t *testing.T) {
+	//A triangle strip forming a quad, with 16 bit indices following the float positions.
+	data := &bytes.Buffer{}
+	binary.Write(data, binary.LittleEndian, []float32{0, 0, 0, 1, 0, 0, 0, 1, 0, 1, 1, 0})
+	binary.Write(data, binary.LittleEndian, []uint16{0, 1, 2, 3})
+	content := `{
+		"asset": {"version": "2.0", "generator": "test"},
+		"extensionsUsed": ["KHR_lights_punctual"],
+		"extensions": {"KHR_lights_punctual": {"lights": [
+			{"name": "Sun", "type": "directional", "intensity": 2},
+			{"type": "spot", "spot": {"outerConeAngle": 0.5}}
+		]}},
+		"scene": 0,
+		"scenes": [{"name": "Main", "nodes": [0, 2]}],
+		"nodes": [
+			{"name": "Quad", "mesh": 0, "children": [1], "rotation": [0, 0.7071068, 0, 0.7071068], "scale": [2, 2, 2]},
+			{"name": "Sun", "extensions": {"KHR_lights_punctual": {"light": 0}}},
+			{"name": "Spot", "matrix": [1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 5, 6, 7, 1],
+				"extensions": {"KHR_lights_punctual": {"light": 1}}}
+		],
+		"meshes": [{"name": "Quad", "primitives": [{"attributes": {"POSITION": 0}, "indices": 1, "mode": 5, "material": 0}]}],
+		"materials": [{"name": "Gold", "pbrMetallicRoughness": {"baseColorFactor": [1, 0.8, 0, 0.5], "metallicFactor": 1, "roughnessFactor": 0.5}, "alphaMode": "BLEND"}],
+		"accessors": [
+			{"bufferView": 0, "componentType": 5126, "count": 4, "type": "VEC3"},
+			{"bufferView": 0, "byteOffset": 48, "componentType": 5123, "count": 4, "type": "SCALAR"}
+		],
+		"bufferViews": [{"buffer": 0, "byteLength": 56}],
+		"buffers": [{"byteLength": 56, "uri": "data:application/octet-stream;base64,` + base64.StdEncoding.EncodeToString(data.Bytes()) + `"}]
+	}`
+	collada, err := LoadGltfFromReader(strings.NewReader(content), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scene := collada.DefaultVisualScene()
+	if scene.Name != "Main" || len(scene.Node) != 2 || collada.Asset.Contributor[0].AuthoringTool != "test" {
+		t.Fatal("wrong scene", scene.Name, len(scene.Node))
+	}
+	quad := scene.Node[0]
+	if rotate := quad.Rotate[0].F(); math.Abs(rotate[1]-1) > 1e-6 || math.Abs(rotate[3]-90) > 1e-4 {
+		t.Error("wrong rotation", rotate)
+	}
+	if m := scene.Node[1].LocalTransform(); m[3] != 5 || m[7] != 6 || m[11] != 7 {
+		t.Error("matrix not converted to row-major", m)
+	}
+	triangles := collada.LibraryGeometries[0].Geometry[0].Mesh.Triangles[0]
+	if triangles.Count != 2 || !reflect.DeepEqual(triangles.P.I(), []int{0, 1, 2, 2, 1, 3}) {
+		t.Error("wrong triangle strip", triangles.P.I())
+	}
+	bound := quad.InstanceGeometry[0].BindMaterial.TechniqueCommon.InstanceMaterial[0]
+	if bound.Symbol != triangles.Material || bound.Target != "#Gold-material" {
+		t.Error("wrong material binding", bound)
+	}
+	gold := collada.CommonMaterial(collada.FindMaterial("Gold-material"))
+	if alpha, _ := gold.Opacity(); alpha != 0.5 {
+		t.Error("wrong opacity", alpha)
+	}
+	if shininess, _ := gold.Shininess.Float(); shininess != 6 {
+		t.Error("wrong shininess", shininess)
+	}
+	id, _ := quad.Node[0].InstanceLight[0].Url.Id()
+	sun := collada.FindLight(id)
+	if color := sun.TechniqueCommon.Directional.Color.F(); color[0] != 2 {
+		t.Error("intensity not applied", color)
+	}
+	spot := collada.LibraryLights[0].Light[1].TechniqueCommon.Spot
+	if math.Abs(spot.FalloffAngle.Value-57.29578) > 1e-4 {
+		t.Error("wrong falloff angle", spot.FalloffAngle.Value)
+	}
+	if err := collada.ExportToWriter(&bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadMalformedGltf(t *testing.T) {
+	buffer := `"buffers": [{"byteLength": 16, "uri": "data:application/octet-stream;base64,` + base64.StdEncoding.EncodeToString(make([]byte, 16)) + `"}]`
+	mesh := `"nodes": [{"mesh": 0}], "meshes": [{"primitives": [{"attributes": {"POSITION": 0}}]}]`
+	for name, content := range map[string]string{
+		"material without positions": `{"asset": {"version": "2.0"}, "meshes": [{"primitives": [{"attributes": {}, "material": 5}]}], "nodes": [{"mesh": 0}]}`,
+		"negative view offset": `{"asset": {"version": "2.0"}, ` + mesh + `, ` + buffer + `,
+			"accessors": [{"bufferView": 0, "componentType": 5126, "count": 1, "type": "VEC3"}],
+			"bufferViews": [{"buffer": 0, "byteOffset": -8, "byteLength": 16}]}`,
+		"negative accessor offset": `{"asset": {"version": "2.0"}, ` + mesh + `, ` + buffer + `,
+			"accessors": [{"bufferView": 0, "byteOffset": -4, "componentType": 5126, "count": 1, "type": "VEC3"}],
+			"bufferViews": [{"buffer": 0, "byteLength": 16}]}`,
+		"negative stride": `{"asset": {"version": "2.0"}, ` + mesh + `, ` + buffer + `,
+			"accessors": [{"bufferView": 0, "componentType": 5126, "count": 1, "type": "VEC3"}],
+			"bufferViews": [{"buffer": 0, "byteLength": 16, "byteStride": -12}]}`,
+		"small stride": `{"asset": {"version": "2.0"}, ` + mesh + `, ` + buffer + `,
+			"accessors": [{"bufferView": 0, "componentType": 5126, "count": 1, "type": "VEC3"}],
+			"bufferViews": [{"buffer": 0, "byteLength": 16, "byteStride": 4}]}`,
+		"huge stride": `{"asset": {"version": "2.0"}, ` + mesh + `, ` + buffer + `,
+			"accessors": [{"bufferView": 0, "componentType": 5126, "count": 2, "type": "VEC3"}],
+			"bufferViews": [{"buffer": 0, "byteLength": 16, "byteStride": 4611686018427387904}]}`,
+		"negative count": `{"asset": {"version": "2.0"}, ` + mesh + `, ` + buffer + `,
+			"accessors": [{"bufferView": 0, "componentType": 5126, "count": -1, "type": "VEC3"}],
+			"bufferViews": [{"buffer": 0, "byteLength": 16}]}`,
+	} {
+		if _, err := LoadGltfFromReader(strings.NewReader(content), nil); err == nil {
+			t.Error("expected error for", name)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package collada
+
+import (
+	"fmt"
+	"math"
+)
+
+//hullFace is a triangle of a convex hull with its outward unit normal and distance from the origin.
+type hullFace struct {
+	a, b, c    int
+	nx, ny, nz float64
+	d          float64
+	//outside holds the points in front of the face not yet added to the hull.
+	outside []int
+}
+
+//ConvexHull computes the convex hull of points given by their x, y and z components.
+//It returns the points on the hull and the triangles indexing them, wound counter-clockwise seen from outside.
+//Fewer than four points, or points that all lie in a plane, have no hull and are an error.
+func ConvexHull(points []float64) ([]float64, []int, error) {
+	if len(points)%3 != 0 {
+		return nil, nil, fmt.Errorf("collada: %d point components is not a multiple of 3", len(points))
+	}
+	n := len(points) / 3
+	if n < 4 {
+		return nil, nil, fmt.Errorf("collada: a convex hull needs at least 4 points, not %d", n)
+	}
+	at := func(i int) (float64, float64, float64) {
+		return points[3*i], points[3*i+1], points[3*i+2]
+	}
+	//the initial tetrahedron starts from the extreme points along the axis of largest extent
+	lo, hi := [3]int{}, [3]int{}
+	for i := 1; i < n; i++ {
+		for k := 0; k < 3; k++ {
+			if points[3*i+k] < points[3*lo[k]+k] {
+				lo[k] = i
+			}
+			if points[3*i+k] > points[3*hi[k]+k] {
+				hi[k] = i
+			}
+		}
+	}
+	axis, scale := 0, 0.0
+	for k := 0; k < 3; k++ {
+		if extent := points[3*hi[k]+k] - points[3*lo[k]+k]; extent > scale {
+			axis, scale = k, extent
+		}
+	}
+	if scale == 0 {
+		return nil, nil, fmt.Errorf("collada: points are coincident and have no convex hull")
+	}
+	eps := scale * 1e-9
+	i0, i1 := lo[axis], hi[axis]
+	x0, y0, z0 := at(i0)
+	x1, y1, z1 := at(i1)
+	i2, best := -1, eps
+	for i := 0; i < n; i++ {
+		x, y, z := at(i)
+		cx, cy, cz := cross(x-x0, y-y0, z-z0, x1-x0, y1-y0, z1-z0)
+		if d := math.Sqrt(cx*cx+cy*cy+cz*cz) / scale; d > best {
+			i2, best = i, d
+		}
+	}
+	if i2 < 0 {
+		return nil, nil, fmt.Errorf("collada: points are collinear and have no convex hull")
+	}
+	newFace := func(a, b, c int) *hullFace {
+		ax, ay, az := at(a)
+		bx, by, bz := at(b)
+		cx, cy, cz := at(c)
+		nx, ny, nz := normalize(cross(bx-ax, by-ay, bz-az, cx-ax, cy-ay, cz-az))
+		return &hullFace{a: a, b: b, c: c, nx: nx, ny: ny, nz: nz, d: nx*ax + ny*ay + nz*az}
+	}
+	distance := func(face *hullFace, i int) float64 {
+		x, y, z := at(i)
+		return face.nx*x + face.ny*y + face.nz*z - face.d
+	}
+	base := newFace(i0, i1, i2)
+	i3, best := -1, eps
+	for i := 0; i < n; i++ {
+		if d := math.Abs(distance(base, i)); d > best {
+			i3, best = i, d
+		}
+	}
+	if i3 < 0 {
+		return nil, nil, fmt.Errorf("collada: points are coplanar and have no convex hull")
+	}
+	if distance(base, i3) > 0 {
+		i1, i2 = i2, i1
+	}
+	faces := []*hullFace{newFace(i0, i1, i2), newFace(i1, i0, i3), newFace(i2, i1, i3), newFace(i0, i2, i3)}
+	assign := func(p int, faces []*hullFace) bool {
+		for _, face := range faces {
+			if distance(face, p) > eps {
+				face.outside = append(face.outside, p)
+				return true
+			}
+		}
+		return false
+	}
+	for p := 0; p < n; p++ {
+		if p != i0 && p != i1 && p != i2 && p != i3 {
+			assign(p, faces)
+		}
+	}
+
+	//the farthest point outside a face replaces the faces it sees with a cone of faces on the edges bounding them,
+	//so that points which end up on a face of the hull are never added
+	for {
+		var next *hullFace
+		for _, face := range faces {
+			if len(face.outside) > 0 {
+				next = face
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		p, best := next.outside[0], distance(next, next.outside[0])
+		for _, q := range next.outside[1:] {
+			if d := distance(next, q); d > best {
+				p, best = q, d
+			}
+		}
+		visible := make(map[*hullFace]bool)
+		edges := make(map[[2]int]bool)
+		for _, face := range faces {
+			if distance(face, p) > eps {
+				visible[face] = true
+				edges[[2]int{face.a, face.b}] = true
+				edges[[2]int{face.b, face.c}] = true
+				edges[[2]int{face.c, face.a}] = true
+			}
+		}
+		kept := make([]*hullFace, 0, len(faces))
+		cone := []*hullFace{}
+		orphans := []int{}
+		for _, face := range faces {
+			if !visible[face] {
+				kept = append(kept, face)
+				continue
+			}
+			for _, edge := range [][2]int{{face.a, face.b}, {face.b, face.c}, {face.c, face.a}} {
+				if !edges[[2]int{edge[1], edge[0]}] {
+					cone = append(cone, newFace(edge[0], edge[1], p))
+				}
+			}
+			orphans = append(orphans, face.outside...)
+		}
+		for _, q := range orphans {
+			if q != p && !assign(q, cone) {
+				assign(q, kept)
+			}
+		}
+		faces = append(kept, cone...)
+	}
+
+	//keep only the points on the hull, in the order the faces use them
+	index := make(map[int]int)
+	vertices := []float64{}
+	triangles := make([]int, 0, 3*len(faces))
+	for _, face := range faces {
+		for _, i := range []int{face.a, face.b, face.c} {
+			j, ok := index[i]
+			if !ok {
+				j = len(index)
+				index[i] = j
+				vertices = append(vertices, points[3*i:3*i+3]...)
+			}
+			triangles = append(triangles, j)
+		}
+	}
+	return vertices, triangles, nil
+}
+
+//positions returns the POSITION values of the vertices of the mesh.
+func (mesh *Mesh) positions() ([]float64, error) {
+	for _, input := range mesh.Vertices.Input {
+		if input.Semantic != "POSITION" {
+			continue
+		}
+		source, err := mesh.source(input.Source)
+		if err != nil {
+			return nil, err
+		}
+		values, size := source.Values()
+		if size != 3 {
+			return nil, fmt.Errorf("collada: mesh positions have %d components, not 3", size)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("collada: mesh has no POSITION input")
+}
+
+//ConvexHull computes the convex hull of the positions of the mesh as a <convex_mesh> of triangles.
+//Its source and vertices are given ids starting with id.
+func (mesh *Mesh) ConvexHull(id Id) (*ConvexMesh, error) {
+	positions, err := mesh.positions()
+	if err != nil {
+		return nil, err
+	}
+	vertices, triangles, err := ConvexHull(positions)
+	if err != nil {
+		return nil, err
+	}
+	source := floatSource(id+"-positions", id+"-positions-array", vertices, "X", "Y", "Z")
+	p := make(IntValues, len(triangles))
+	for i, index := range triangles {
+		p[i] = int32(index)
+	}
+	convex := &ConvexMesh{
+		Source: []*Source{source},
+		Vertices: &Vertices{
+			HasId: HasId{id + "-vertices"},
+			Input: []*InputUnshared{{Semantic: "POSITION", Source: source.Id.Uri()}},
+		},
+	}
+	convex.Triangles = []*Triangles{{
+		HasCount:       HasCount{len(triangles) / 3},
+		HasSharedInput: HasSharedInput{[]*InputShared{{Semantic: "VERTEX", Source: convex.Vertices.Id.Uri()}}},
+		HasP:           HasP{&P{Ints{p}}},
+	}}
+	return convex, nil
+}
+
+//Mesh returns the convex mesh as a <mesh> sharing its sources and primitives, to read it with Primitives.
+//A convex mesh given by ConvexHullOf alone has no vertices; see ResolveConvexMesh.
+func (convex *ConvexMesh) Mesh() *Mesh {
+	mesh := &Mesh{
+		Source:     convex.Source,
+		Lines:      convex.Lines,
+		Linestrips: convex.Linestrips,
+		Polygons:   convex.Polygons,
+		Polylist:   convex.Polylist,
+		Triangles:  convex.Triangles,
+		Trifans:    convex.Trifans,
+		Tristrips:  convex.Tristrips,
+		HasExtra:   convex.HasExtra,
+	}
+	if convex.Vertices != nil {
+		mesh.Vertices = *convex.Vertices
+	}
+	return mesh
+}
+
+//ResolveConvexMesh returns a convex mesh with explicit vertices and primitives: the mesh itself, or if it is
+//given by ConvexHullOf, the hull of the referenced geometry computed by Mesh.ConvexHull.
+func (collada *Collada) ResolveConvexMesh(convex *ConvexMesh) (*ConvexMesh, error) {
+	for depth := 0; convex.ConvexHullOf != ""; depth++ {
+		id, ok := convex.ConvexHullOf.Id()
+		if !ok {
+			return nil, fmt.Errorf("collada: convex_hull_of references external geometry %q", convex.ConvexHullOf)
+		}
+		geometry := collada.FindGeometry(id)
+		switch {
+		case geometry == nil:
+			return nil, fmt.Errorf("collada: convex_hull_of references missing geometry %q", id)
+		case depth > 16:
+			return nil, fmt.Errorf("collada: convex_hull_of references are nested too deeply")
+		case geometry.Mesh != nil:
+			return geometry.Mesh.ConvexHull(id + "-hull")
+		case geometry.ConvexMesh != nil:
+			convex = geometry.ConvexMesh
+		default:
+			return nil, fmt.Errorf("collada: convex_hull_of references geometry %q without a mesh", id)
+		}
+	}
+	if convex.Vertices == nil {
+		return nil, fmt.Errorf("collada: convex mesh has no vertices")
+	}
+	return convex, nil
+}
@@ -0,0 +1,118 @@
+package collada
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+//checkHull verifies that every point lies behind every outward facing triangle of a hull.
+func checkHull(t *testing.T, points, vertices []float64, triangles []int) {
+	for i := 0; i < len(triangles); i += 3 {
+		a, b, c := triangles[i], triangles[i+1], triangles[i+2]
+		ax, ay, az := vertices[3*a], vertices[3*a+1], vertices[3*a+2]
+		nx, ny, nz := cross(vertices[3*b]-ax, vertices[3*b+1]-ay, vertices[3*b+2]-az, vertices[3*c]-ax, vertices[3*c+1]-ay, vertices[3*c+2]-az)
+		nx, ny, nz = normalize(nx, ny, nz)
+		for j := 0; j < len(points); j += 3 {
+			if d := nx*(points[j]-ax) + ny*(points[j+1]-ay) + nz*(points[j+2]-az); d > 1e-9 {
+				t.Fatalf("point %d is %v outside triangle %d", j/3, d, i/3)
+			}
+		}
+	}
+}
+
+func TestConvexHull(t *testing.T) {
+	cube := []float64{}
+	for i := 0; i < 8; i++ {
+		cube = append(cube, float64(i&1), float64(i>>1&1), float64(i>>2&1))
+	}
+	points := append([]float64{0.5, 0.5, 0.5, 0.25, 0.75, 0.5, 0.5, 0, 0.5}, cube...)
+	vertices, triangles, err := ConvexHull(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vertices) != 24 || len(triangles) != 36 {
+		t.Fatalf("got %d vertices and %d triangles, expected 8 and 12", len(vertices)/3, len(triangles)/3)
+	}
+	checkHull(t, points, vertices, triangles)
+
+	random := rand.New(rand.NewSource(1))
+	sphere := []float64{}
+	for i := 0; i < 500; i++ {
+		x, y, z := normalize(random.NormFloat64(), random.NormFloat64(), random.NormFloat64())
+		r := math.Sqrt(random.Float64())
+		sphere = append(sphere, x*r, y*r, z*r)
+	}
+	vertices, triangles, err = ConvexHull(sphere)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHull(t, sphere, vertices, triangles)
+	//a closed triangulated surface of genus 0 has 2V - 4 faces
+	if len(triangles)/3 != 2*len(vertices)/3-4 {
+		t.Errorf("hull of %d vertices has %d triangles", len(vertices)/3, len(triangles)/3)
+	}
+
+	if _, _, err := ConvexHull([]float64{0, 0, 0, 1, 0, 0, 0, 1, 0, 1, 1, 0}); err == nil {
+		t.Error("expected error for coplanar points")
+	}
+	if _, _, err := ConvexHull([]float64{0, 0, 0, 1, 0, 0, 0, 1, 0}); err == nil {
+		t.Error("expected error for too few points")
+	}
+}
+
+func TestConvexMesh(t *testing.T) {
+	doc := NewDocument()
+	positions := []float64{0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 1, 0.1, 0.1, 0.1}
+	geometry, err := doc.AddGeometry("tetra", positions, nil, nil, []int{0, 2, 1, 0, 1, 3, 0, 3, 2, 1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	convex, err := geometry.Mesh.ConvexHull("tetra-hull")
+	if err != nil {
+		t.Fatal(err)
+	}
+	primitives, err := convex.Mesh().Primitives()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primitives) != 1 || len(primitives[0].VCount) != 4 || len(primitives[0].Attribute("POSITION").Values) != 12 {
+		t.Fatal("wrong convex hull mesh")
+	}
+	library := doc.Collada.LibraryGeometries[0]
+	library.Geometry = append(library.Geometry,
+		&Geometry{HasId: HasId{"tetra-collider"}, ConvexMesh: convex},
+		&Geometry{HasId: HasId{"tetra-hull-of"}, ConvexMesh: &ConvexMesh{ConvexHullOf: geometry.Id.Uri()}},
+	)
+
+	buf := &bytes.Buffer{}
+	if err := doc.Collada.ExportToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	collada, err := LoadDocumentFromReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collider := collada.FindGeometry("tetra-collider").ConvexMesh
+	if collider == nil || collider.Vertices.Id != "tetra-hull-vertices" || len(collider.Triangles) != 1 || collider.Triangles[0].Count != 4 {
+		t.Fatal("wrong convex mesh")
+	}
+	if resolved, err := collada.ResolveConvexMesh(collider); err != nil || resolved != collider {
+		t.Error("explicit convex mesh should resolve to itself")
+	}
+	hullOf := collada.FindGeometry("tetra-hull-of").ConvexMesh
+	if hullOf.ConvexHullOf != "#tetra-mesh" || hullOf.Vertices != nil {
+		t.Fatal("wrong convex_hull_of")
+	}
+	resolved, err := collada.ResolveConvexMesh(hullOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Triangles[0].Count != 4 || resolved.Vertices.Id != "tetra-mesh-hull-vertices" {
+		t.Error("wrong resolved convex mesh")
+	}
+	if _, err := collada.ResolveConvexMesh(&ConvexMesh{ConvexHullOf: "#missing"}); err == nil {
+		t.Error("expected error for missing geometry")
+	}
+}
@@ -0,0 +1,129 @@
+package collada
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+//Source returns the element initializing an image: its own <init_from>, or else the first
+//<init_from> of the 2D, 3D or cube map image it creates. It returns nil if there is none.
+func (image *Image) Source() *InitFrom {
+	if image.InitFrom != nil {
+		return image.InitFrom
+	}
+	var inits []*InitFrom
+	switch {
+	case image.Create2d != nil:
+		inits = image.Create2d.InitFrom
+	case image.Create3d != nil:
+		inits = image.Create3d.InitFrom
+	case image.CreateCube != nil:
+		inits = image.CreateCube.InitFrom
+	}
+	if len(inits) > 0 {
+		return inits[0]
+	}
+	return nil
+}
+
+//Uri returns the reference to the external resource initializing an image, if it is not embedded.
+func (init *InitFrom) Uri() Uri {
+	if init.Ref != "" {
+		return Uri(strings.TrimSpace(string(init.Ref)))
+	}
+	if init.Hex != nil {
+		return ""
+	}
+	return Uri(strings.TrimSpace(string(init.Value)))
+}
+
+//SetUri replaces the reference to the external resource initializing an image, keeping the form it was written in.
+func (init *InitFrom) SetUri(uri Uri) {
+	if init.Ref == "" && strings.TrimSpace(string(init.Value)) != "" {
+		init.Value = uri
+		return
+	}
+	init.Ref = uri
+	init.Value = ""
+}
+
+//Embedded returns the data and format of an image embedded in the document as <hex>.
+func (image *Image) Embedded() ([]byte, string, bool) {
+	init := image.Source()
+	if init == nil || init.Hex == nil {
+		return nil, "", false
+	}
+	return init.Hex.V, init.Hex.Format, true
+}
+
+//windowsPath matches absolute Windows paths, which are common in exported documents but are not uris.
+var windowsPath = regexp.MustCompile(`^([A-Za-z]:[\\/]|\\\\)`)
+
+//ResolvePath returns the file referenced by uri, resolving relative references against the base
+//uri of the document, if it has one, and then against location, the path of the document.
+//An empty location resolves against the working directory.
+//Absolute Windows paths are returned unchanged; references which are not local files are an error.
+func (collada *Collada) ResolvePath(uri Uri, location string) (string, error) {
+	text := strings.TrimSpace(string(uri))
+	if text == "" {
+		return "", fmt.Errorf("collada: empty file reference")
+	}
+	if windowsPath.MatchString(text) {
+		return text, nil
+	}
+	ref, err := url.Parse(strings.Replace(text, "\\", "/", -1))
+	if err != nil {
+		return "", err
+	}
+	if location == "" {
+		//The name of the document is dropped when resolving, leaving the working directory.
+		location = "document"
+	}
+	document, err := filepath.Abs(location)
+	if err != nil {
+		return "", err
+	}
+	base := &url.URL{Scheme: "file", Path: filepath.ToSlash(document)}
+	if collada.Base != "" {
+		documentBase, err := url.Parse(string(collada.Base))
+		if err != nil {
+			return "", err
+		}
+		base = base.ResolveReference(documentBase)
+	}
+	resolved := base.ResolveReference(ref)
+	if resolved.Scheme != "file" || (resolved.Host != "" && resolved.Host != "localhost") {
+		return "", fmt.Errorf("collada: %q is not a local file", text)
+	}
+	path := resolved.Path
+	if windowsPath.MatchString(strings.TrimPrefix(path, "/")) {
+		return strings.TrimPrefix(path, "/"), nil
+	}
+	return filepath.FromSlash(path), nil
+}
+
+//ImagePath returns the file initializing an image as resolved by ResolvePath.
+func (collada *Collada) ImagePath(image *Image, location string) (string, error) {
+	init := image.Source()
+	if init == nil || init.Uri() == "" {
+		return "", fmt.Errorf("collada: image %q does not reference a file", image.Id)
+	}
+	return collada.ResolvePath(init.Uri(), location)
+}
+
+//ImageData returns the contents of an image, either embedded in the document or read from the
+//file found by ImagePath.
+func (collada *Collada) ImageData(image *Image, location string) ([]byte, error) {
+	if data, _, ok := image.Embedded(); ok {
+		return data, nil
+	}
+	path, err := collada.ImagePath(image, location)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,96 @@
+package collada
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const imagesDocument = `<?xml version="1.0" encoding="utf-8"?>
+<COLLADA xmlns="http://www.collada.org/2008/03/COLLADASchema" version="1.5.0">
+  <library_images>
+    <image id="wood" name="wood">
+      <init_from mips_generate="true"><ref>textures/wood%20grain.png</ref></init_from>
+    </image>
+    <image id="legacy">
+      <init_from>C:\Users\artist\legacy.png</init_from>
+    </image>
+    <image id="pixel">
+      <init_from><hex format="R8G8B8A8">FF00 00ff</hex></init_from>
+    </image>
+    <image id="sky">
+      <create_cube>
+        <size width="256"/>
+        <mips levels="0" auto_generate="true"/>
+        <format><hint channels="RGB" range="UNORM" precision="MID"/></format>
+        <init_from face="POSITIVE_X"><ref>sky_px.png</ref></init_from>
+        <init_from face="NEGATIVE_X"><ref>sky_nx.png</ref></init_from>
+      </create_cube>
+    </image>
+  </library_images>
+</COLLADA>`
+
+func TestImages(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(imagesDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	location := filepath.Join("models", "scene.dae")
+	wood := collada.FindImage("wood")
+	path, err := collada.ImagePath(wood, location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, _ := filepath.Abs(filepath.Join("models", "textures", "wood grain.png")); path != expected {
+		t.Error("wrong path", path, expected)
+	}
+	if path, _ := collada.ImagePath(collada.FindImage("legacy"), location); path != `C:\Users\artist\legacy.png` {
+		t.Error("wrong windows path", path)
+	}
+	data, err := collada.ImageData(collada.FindImage("pixel"), location)
+	if err != nil || !bytes.Equal(data, []byte{0xff, 0, 0, 0xff}) {
+		t.Error("wrong embedded data", data, err)
+	}
+	sky := collada.FindImage("sky")
+	if sky.CreateCube.Size.Width != 256 || sky.CreateCube.Format.Hint.Channels != "RGB" || sky.Source().Face != "POSITIVE_X" {
+		t.Error("wrong cube map", sky.CreateCube)
+	}
+
+	collada.Base = "file:///assets/shared/"
+	if path, _ := collada.ImagePath(sky, location); path != filepath.FromSlash("/assets/shared/sky_px.png") {
+		t.Error("base not applied", path)
+	}
+	collada.Base = "../library/"
+	expected, _ := filepath.Abs(filepath.Join("library", "textures", "wood grain.png"))
+	if path, _ := collada.ImagePath(wood, location); path != expected {
+		t.Error("relative base not applied", path, expected)
+	}
+	if _, err := collada.ResolvePath("http://example.com/wood.png", location); err == nil {
+		t.Error("expected remote uri error")
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buffer); err != nil {
+		t.Fatal(err)
+	}
+	exported := buffer.String()
+	for _, text := range []string{"<ref>textures/wood%20grain.png</ref>", `<hex format="R8G8B8A8">FF0000FF</hex>`, `<init_from face="NEGATIVE_X">`} {
+		if !strings.Contains(exported, text) {
+			t.Error("missing from export", text)
+		}
+	}
+}
+
+func TestImageFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "wood.png"), []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	image := &Image{InitFrom: &InitFrom{Ref: "wood.png"}}
+	data, err := (&Collada{}).ImageData(image, filepath.Join(dir, "scene.dae"))
+	if err != nil || string(data) != "png" {
+		t.Error("wrong image data", string(data), err)
+	}
+}
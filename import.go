@@ -8,6 +8,8 @@ import (
 	"encoding/xml"
 	"io"
 	"os"
+	"runtime"
+	"time"
 )
 
 type Version string
@@ -39,37 +41,66 @@ const (
 
 //Animation ategorizes the declaration of animation information.
 type Animation struct {
-	//TODO
+	HasId
+	HasName
+	HasAsset
+	Source    []*Source    `xml:"source"`
+	Sampler   []*Sampler   `xml:"sampler"`
+	Channel   []*Channel   `xml:"channel"`
+	Animation []*Animation `xml:"animation"`
+	HasExtra
 }
 
 //AnimationClip defines a section of the animation curves to be used together as an animation clip.
 type AnimationClip struct {
-	//TODO
+	HasId
+	HasName
+	Start float64 `xml:"start,attr,omitempty"`
+	End   float64 `xml:"end,attr,omitempty"`
+	HasAsset
+	InstanceAnimation []*InstanceAnimation `xml:"instance_animation"`
+	InstanceFormula   []*InstanceFormula   `xml:"instance_formula"`
+	HasExtra
 }
 
 //Channel declares an output channel of an animation.
 type Channel struct {
-	//TODO
+	Source Uri    `xml:"source,attr"`
+	Target string `xml:"target,attr"`
 }
 
 //InstanceAnimation instantiates a COLLADA animation resource.
 type InstanceAnimation struct {
-	//TODO
+	HasSid
+	HasName
+	HasUrl
+	HasExtra
 }
 
 //LibraryAnimationClips provides a library in which to place <animation_clip> elements.
 type LibraryAnimationClips struct {
-	//TODO
+	HasId
+	HasName
+	HasAsset
+	AnimationClip []*AnimationClip `xml:"animation_clip"`
+	HasExtra
 }
 
 //LibraryAnimations provides a library in which to place <animation> elements.
 type LibraryAnimations struct {
-	//TODO
+	HasId
+	HasName
+	HasAsset
+	Animation []*Animation `xml:"animation"`
+	HasExtra
 }
 
 //Sampler declares an interpolation sampling function for an animation.
 type Sampler struct {
-	//TODO
+	HasId
+	PreBehavior  string           `xml:"pre_behavior,attr,omitempty"`
+	PostBehavior string           `xml:"post_behavior,attr,omitempty"`
+	Input        []*InputUnshared `xml:"input"`
 }
 
 //Camera declares a view into the scene hierarchy or scene graph.
@@ -108,19 +139,33 @@ type LibraryCameras struct {
 
 //Optics represents the apparatus on a camera that projects the image onto the image sensor.
 type Optics struct {
-	HasTechniqueCommon
+	TechniqueCommon OpticsTechniqueCommon `xml:"technique_common"`
 	HasTechnique
 	HasExtra
 }
 
+//OpticsTechniqueCommon specifies the projection of a camera.
+type OpticsTechniqueCommon struct {
+	Orthographic *Orthographic `xml:"orthographic"`
+	Perspective  *Perspective  `xml:"perspective"`
+}
+
 //Orthographic describes the field of view of an orthographic camera.
 type Orthographic struct {
-	//TODO
+	Xmag        *Float `xml:"xmag"`
+	Ymag        *Float `xml:"ymag"`
+	AspectRatio *Float `xml:"aspect_ratio"`
+	Znear       Float  `xml:"znear"`
+	Zfar        Float  `xml:"zfar"`
 }
 
 //Perspective describes the field of view of a perspective camera.
 type Perspective struct {
-	//TODO
+	Xfov        *Float `xml:"xfov"`
+	Yfov        *Float `xml:"yfov"`
+	AspectRatio *Float `xml:"aspect_ratio"`
+	Znear       Float  `xml:"znear"`
+	Zfar        Float  `xml:"zfar"`
 }
 
 //Controller categorizes the declaration of generic control information.
@@ -175,7 +220,11 @@ type VertexWeights struct {
 
 // Accessor declares an access pattern to one of the array elements <float_array>, <int_array>, <Name_array>, <bool_array>, and <IDREF_array>.
 type Accessor struct {
-	//TODO
+	HasCount
+	Offset uint         `xml:"offset,attr,omitempty"`
+	Source Uri          `xml:"source,attr"`
+	Stride uint         `xml:"stride,attr,omitempty"`
+	Param  []*ParamCore `xml:"param"`
 }
 
 // BoolArray declares the storage for a homogenous array of Boolean values.
@@ -224,7 +273,10 @@ type NameArray struct {
 
 // ParamCore declares parametric information for its parent element.
 type ParamCore struct {
-	//TODO
+	HasName
+	HasSid
+	Semantic string `xml:"semantic,attr,omitempty"`
+	Type     string `xml:"type,attr"`
 }
 
 // SidRefArray declares the storage for a homogenous array of scoped-identifier reference values.
@@ -248,10 +300,15 @@ type Source struct {
 	NameArray   *NameArray   `xml:"Name_array"`
 	SidRefArray *SidRefArray `xml:"SIDREF_array"`
 	// TokenArray *TokenArray `xml:"token_array"`
-	HasTechniqueCommon
+	TechniqueCommon *SourceTechniqueCommon `xml:"technique_common"`
 	HasTechnique
 }
 
+//SourceTechniqueCommon specifies how the array of a <source> is read.
+type SourceTechniqueCommon struct {
+	Accessor Accessor `xml:"accessor"`
+}
+
 // InputShared declares the input semantics of a data source.
 type InputShared struct {
 	Offset   uint   `xml:"offset,attr"`
@@ -292,15 +349,29 @@ type ControlVertices struct {
 	//TODO
 }
 
+//ConvexMesh describes a convex mesh, given like a <mesh> or as the convex hull of the geometry referenced by ConvexHullOf.
+type ConvexMesh struct {
+	ConvexHullOf Uri           `xml:"convex_hull_of,attr,omitempty"`
+	Source       []*Source     `xml:"source"`
+	Vertices     *Vertices     `xml:"vertices"`
+	Lines        []*Lines      `xml:"lines"`
+	Linestrips   []*Linestrips `xml:"linestrips"`
+	Polygons     []*Polygons   `xml:"polygons"`
+	Polylist     []*Polylist   `xml:"polylist"`
+	Triangles    []*Triangles  `xml:"triangles"`
+	Trifans      []*Trifans    `xml:"trifans"`
+	Tristrips    []*Tristrips  `xml:"tristrips"`
+	HasExtra
+}
+
 //Geometry describes the visual shape and appearance of an object in a scene.
 type Geometry struct {
 	HasId
 	HasName
 	HasAsset
-	//TODO
-	// ConvexMesh *ConvexMesh `xml:"convex_mesh"`
-	Mesh   *Mesh   `xml:"mesh"`
-	Spline *Spline `xml:"spline"`
+	ConvexMesh *ConvexMesh `xml:"convex_mesh"`
+	Mesh       *Mesh       `xml:"mesh"`
+	Spline     *Spline     `xml:"spline"`
 	//TODO
 	// Brep *Brep `xml:"brep"`
 	HasExtra
@@ -433,7 +504,7 @@ type Vertices struct {
 
 //AmbientCore (core) Describes an ambient light source.
 type AmbientCore struct {
-	//TODO
+	Color Color `xml:"color"`
 }
 
 //Color describes the color of its parent light element.
@@ -444,7 +515,7 @@ type Color struct {
 
 //Directional describes a directional light source.
 type Directional struct {
-	//TODO
+	Color Color `xml:"color"`
 }
 
 //InstanceLight instantiates a COLLADA light resource.
@@ -469,19 +540,35 @@ type Light struct {
 	HasId
 	HasName
 	HasAsset
-	HasTechniqueCommon
+	TechniqueCommon LightTechniqueCommon `xml:"technique_common"`
 	HasTechnique
 	HasExtra
 }
 
+//LightTechniqueCommon specifies the type of a light source.
+type LightTechniqueCommon struct {
+	Ambient     *AmbientCore `xml:"ambient"`
+	Directional *Directional `xml:"directional"`
+	Point       *Point       `xml:"point"`
+	Spot        *Spot        `xml:"spot"`
+}
+
 //Point describes a point light source.
 type Point struct {
-	//TODO
+	Color                Color  `xml:"color"`
+	ConstantAttenuation  *Float `xml:"constant_attenuation"`
+	LinearAttenuation    *Float `xml:"linear_attenuation"`
+	QuadraticAttenuation *Float `xml:"quadratic_attenuation"`
 }
 
 //Spot describes a spot light source.
 type Spot struct {
-	//TODO
+	Color                Color  `xml:"color"`
+	ConstantAttenuation  *Float `xml:"constant_attenuation"`
+	LinearAttenuation    *Float `xml:"linear_attenuation"`
+	QuadraticAttenuation *Float `xml:"quadratic_attenuation"`
+	FalloffAngle         *Float `xml:"falloff_angle"`
+	FalloffExponent      *Float `xml:"falloff_exponent"`
 }
 
 //Formula defines a formula.
@@ -511,17 +598,20 @@ type Unit struct {
 }
 
 //Asset defines asset-management information regarding its parent element.
+//Created and modified times which do not parse are left zero, and their text is kept to be written back.
 type Asset struct {
 	Contributor []*Contributor `xml:"contributor"`
 	Coverage    *Coverage     `xml:"coverage"`
-	Created     string        `xml:"created"`
+	Created     time.Time     `xml:"created"`
 	Keywords    string        `xml:"keywords,omitempty"`
-	Modified    string        `xml:"modified"`
+	Modified    time.Time     `xml:"modified"`
 	Revision    string        `xml:"revision,omitempty"`
 	Subject     string        `xml:"subject,omitempty"`
 	Title       string        `xml:"title,omitempty"`
 	Unit        *Unit         `xml:"unit"`
 	UpAxis      UpAxis        `xml:"up_axis,omitempty"`
+
+	createdText, modifiedText string
 }
 
 //COLLADA declares the root of the document that contains some of the content in the COLLADA schema.
@@ -531,27 +621,27 @@ type Collada struct {
 	Version Version `xml:"version,attr"`
 	Base    Uri     `xml:"base,attr,omitempty"`
 	HasAsset
-	LibraryAnimationClips []*LibraryAnimationClips `xml:"library_animation_clips"`
-	LibraryAnimations     []*LibraryAnimations     `xml:"library_animations"`
-	// LibraryArticulatedSystems []*LibraryArticulatedSystems `xml:"library_animation_clips"`
-	LibraryCameras     []*LibraryCameras     `xml:"library_cameras"`
-	LibraryControllers []*LibraryControllers `xml:"library_controllers"`
-	LibraryLights      []*LibraryLights      `xml:"library_lights"`
-	LibraryImages      []*LibraryImages      `xml:"library_images"`
-	LibraryEffects     []*LibraryEffects     `xml:"library_effects"`
-	// LibraryForceFields []*LibraryForceFields `xml:"library_force_fields"`
-	LibraryFormulas []*LibraryFormulas `xml:"library_formulas"`
-	// LibraryJoints []*LibraryJoints `xml:"library_joints"`
-	// LibraryKinematicModels []*LibraryKinematicModels `xml:"library_kinematic_models"`
-	// LibraryKinematicScenes []*LibraryKinematicScenes `xml:"library_kinematic_scenes"`
-	LibraryMaterials  []*LibraryMaterials  `xml:"library_materials"`
-	LibraryGeometries []*LibraryGeometries `xml:"library_geometries"`
-	// LibraryPhysicsNodes []*LibraryPhysicsNodes `xml:"library_physics_nodes"`
-	// LibraryPhysicsMaterials []*LibraryPhysicsMaterials `xml:"library_physics_materials"`
-	// LibraryPhysicsScenes []*LibraryPhysicsScenes `xml:"library_physics_scenes"`
-	// LibraryPhysicsScenes []*LibraryPhysicsScenes `xml:"library_physics_scenes"`
-	LibraryVisualScenes []*LibraryVisualScenes `xml:"library_visual_scenes"`
-	Scene               *Scene                `xml:"scene"`
+	LibraryAnimationClips     []*LibraryAnimationClips     `xml:"library_animation_clips"`
+	LibraryAnimations         []*LibraryAnimations         `xml:"library_animations"`
+	LibraryArticulatedSystems []*LibraryArticulatedSystems `xml:"library_articulated_systems"`
+	LibraryCameras            []*LibraryCameras            `xml:"library_cameras"`
+	LibraryControllers        []*LibraryControllers        `xml:"library_controllers"`
+	LibraryLights             []*LibraryLights             `xml:"library_lights"`
+	LibraryImages             []*LibraryImages             `xml:"library_images"`
+	LibraryEffects            []*LibraryEffects            `xml:"library_effects"`
+	LibraryForceFields        []*LibraryForceFields        `xml:"library_force_fields"`
+	LibraryFormulas           []*LibraryFormulas           `xml:"library_formulas"`
+	LibraryJoints             []*LibraryJoints             `xml:"library_joints"`
+	LibraryKinematicsModels   []*LibraryKinematicsModels   `xml:"library_kinematics_models"`
+	LibraryKinematicsScenes   []*LibraryKinematicsScenes   `xml:"library_kinematics_scenes"`
+	LibraryMaterials          []*LibraryMaterials          `xml:"library_materials"`
+	LibraryNodes              []*LibraryNodes              `xml:"library_nodes"`
+	LibraryGeometries         []*LibraryGeometries         `xml:"library_geometries"`
+	LibraryPhysicsMaterials   []*LibraryPhysicsMaterials   `xml:"library_physics_materials"`
+	LibraryPhysicsModels      []*LibraryPhysicsModels      `xml:"library_physics_models"`
+	LibraryPhysicsScenes      []*LibraryPhysicsScenes      `xml:"library_physics_scenes"`
+	LibraryVisualScenes       []*LibraryVisualScenes       `xml:"library_visual_scenes"`
+	Scene                     *Scene                       `xml:"scene"`
 	HasExtra
 }
 
@@ -573,7 +663,13 @@ type GeographicLocation struct {
 
 //EvaluateScene declares information specifying how to evaluate a <visual_scene>.
 type EvaluateScene struct {
-	//TODO
+	HasId
+	HasName
+	HasSid
+	Enable *bool `xml:"enable,attr"`
+	HasAsset
+	Render []*Render `xml:"render"`
+	HasExtra
 }
 
 //InstanceNode instantiates a COLLADA node resource.
@@ -595,7 +691,11 @@ type InstanceVisualScene struct {
 
 //LibraryNodes provides a library in which to place <node> elements.
 type LibraryNodes struct {
-	//TODO
+	HasId
+	HasName
+	HasAsset
+	HasNodes
+	HasExtra
 }
 
 //LibraryVisualScenes provides a library in which to place <visual_scene> elements.
@@ -632,16 +732,31 @@ type Node struct {
 
 //Scene embodies the entire set of information that can be visualized from the contents of a COLLADA resource.
 type Scene struct {
-	InstancePhysicsScene    []*InstancePhysicsScene   `xml:"instance_physics_scene"`
+	InstancePhysicsScene    []*InstancePhysicsScene  `xml:"instance_physics_scene"`
 	InstanceVisualScene     *InstanceVisualScene     `xml:"instance_visual_scene"`
 	InstanceKinematicsScene *InstanceKinematicsScene `xml:"instance_kinematics_scene"`
 	HasExtra
 }
+
+//InstancePhysicsScene instantiates a physics scene.
 type InstancePhysicsScene struct {
-	//TODO
+	HasSid
+	HasName
+	HasUrl
+	HasExtra
 }
+
+//InstanceKinematicsScene instantiates a kinematics scene, binding its models to nodes of the visual scene.
 type InstanceKinematicsScene struct {
-	//TODO
+	HasSid
+	HasName
+	HasUrl
+	HasAsset
+	HasNewparam
+	Setparam            []*Setparam            `xml:"setparam"`
+	BindKinematicsModel []*BindKinematicsModel `xml:"bind_kinematics_model"`
+	BindJointAxis       []*BindJointAxis       `xml:"bind_joint_axis"`
+	HasExtra
 }
 
 //VisualScene embodies the entire set of information that can be visualized from the contents of a COLLADA resource.
@@ -690,14 +805,27 @@ type Translate struct {
 	Float3
 }
 
+//Transform is a <translate> or <rotate> of a rigid transform. Exactly one field is set,
+//except for elements of other kinds decoded in their place, which have neither.
+type Transform struct {
+	Translate *Translate
+	Rotate    *Rotate
+}
+
+//Transforms is a sequence of translations and rotations kept in document order.
+type Transforms []*Transform
+
 //Annotate Adds a strongly typed annotation remark to the parent object.
 type Annotate struct {
-	//TODO
+	HasName
+	ParamValue
 }
 
 //BindVertexInput Binds geometry vertex inputs to effect vertex inputs upon instantiation.
 type BindVertexInput struct {
-	//TODO
+	Semantic      string `xml:"semantic,attr"`
+	InputSemantic string `xml:"input_semantic,attr"`
+	InputSet      *uint  `xml:"input_set,attr"`
 }
 
 //Effect Provides a self-contained description of a COLLADA effect.
@@ -740,11 +868,12 @@ type TechniqueFx struct {
 	HasSid
 	HasAsset
 	HasAnnotate
+	HasNewparam
 	Blinn      *Blinn      `xml:"blinn"`
 	ConstantFx *ConstantFx `xml:"constant"`
 	Lambert    *Lambert    `xml:"lambert"`
-	Phone      *Phong      `xml:"phong"`
-	Pass       *Pass       `xml:"pass"`
+	Phong      *Phong      `xml:"phong"`
+	Pass       []*Pass     `xml:"pass"`
 	HasExtra
 }
 
@@ -757,15 +886,32 @@ type TechniqueHint struct {
 
 //BindMaterial Binds a specific material to a piece of geometry, binding varying and uniform parameters at the same time.
 type BindMaterial struct {
-	Param []*ParamCore `xml:"param"`
-	HasTechniqueCommon
+	Param           []*ParamCore                `xml:"param"`
+	TechniqueCommon BindMaterialTechniqueCommon `xml:"technique_common"`
 	HasTechnique
 	HasExtra
 }
 
+//BindMaterialTechniqueCommon lists the materials bound to the symbols of a geometry.
+type BindMaterialTechniqueCommon struct {
+	InstanceMaterial []*InstanceMaterialGeometry `xml:"instance_material"`
+}
+
 //InstanceMaterialGeometry Instantiates a COLLADA material resource.
 type InstanceMaterialGeometry struct {
-	//TODO
+	HasSid
+	HasName
+	Target          Uri                `xml:"target,attr"`
+	Symbol          string             `xml:"symbol,attr"`
+	Bind            []*Bind            `xml:"bind"`
+	BindVertexInput []*BindVertexInput `xml:"bind_vertex_input"`
+	HasExtra
+}
+
+//Bind binds an effect parameter to a value in the scene, such as the transform of a node, when a material is instantiated.
+type Bind struct {
+	Semantic string `xml:"semantic,attr"`
+	Target   string `xml:"target,attr"`
 }
 
 //LibraryMaterials Provides a library in which to place <material> assets.
@@ -788,22 +934,104 @@ type Material struct {
 
 //Array Creates a parameter of a one-dimensional array type.
 type Array struct {
-	//TODO
+	Length    uint
+	Resizable *bool
+	//Element holds the values of the array, each of the same type.
+	Element []*ParamValue
 }
 
 //Modifier Provides additional information about the volatility or linkage of a <newparam>declaration.
 type Modifier struct {
-	//TODO
+	Value ModifierKind `xml:",chardata"`
 }
 
+//ModifierKind names the volatility or linkage a <modifier> gives a parameter.
+type ModifierKind string
+
+const (
+	ModifierConst    ModifierKind = "CONST"
+	ModifierUniform  ModifierKind = "UNIFORM"
+	ModifierVarying  ModifierKind = "VARYING"
+	ModifierStatic   ModifierKind = "STATIC"
+	ModifierVolatile ModifierKind = "VOLATILE"
+	ModifierExtern   ModifierKind = "EXTERN"
+	ModifierShared   ModifierKind = "SHARED"
+)
+
 //Newparam Creates a new, named parameter object and assigns it a type and an initial value. See Chapter 5: Core Elements Reference.
 type Newparam struct {
-	//TODO
+	HasSid
+	HasAnnotate
+	Semantic *Semantic `xml:"semantic"`
+	Modifier *Modifier `xml:"modifier"`
+	ParamValue
+}
+
+//ParamValue holds the typed value of a <newparam>, <setparam>, <annotate> or array element. Exactly one field is set,
+//and the name of its element is the type of the value.
+type ParamValue struct {
+	Bool     *bool     `xml:"bool"`
+	Bool2    *Bools    `xml:"bool2"`
+	Bool3    *Bools    `xml:"bool3"`
+	Bool4    *Bools    `xml:"bool4"`
+	Int      *int      `xml:"int"`
+	Int2     *Ints     `xml:"int2"`
+	Int3     *Ints     `xml:"int3"`
+	Int4     *Ints     `xml:"int4"`
+	Float    *float64  `xml:"float"`
+	Float2   *Floats   `xml:"float2"`
+	Float3   *Float3   `xml:"float3"`
+	Float4   *Float4   `xml:"float4"`
+	Float2x1 *Floats   `xml:"float2x1"`
+	Float2x2 *Floats   `xml:"float2x2"`
+	Float2x3 *Floats   `xml:"float2x3"`
+	Float2x4 *Floats   `xml:"float2x4"`
+	Float3x1 *Floats   `xml:"float3x1"`
+	Float3x2 *Floats   `xml:"float3x2"`
+	Float3x3 *Float3x3 `xml:"float3x3"`
+	Float3x4 *Floats   `xml:"float3x4"`
+	Float4x1 *Floats   `xml:"float4x1"`
+	Float4x2 *Floats   `xml:"float4x2"`
+	Float4x3 *Floats   `xml:"float4x3"`
+	Float4x4 *Float4x4 `xml:"float4x4"`
+	Enum     *string   `xml:"enum"`
+	String   *string   `xml:"string"`
+
+	Surface      *Surface      `xml:"surface"`
+	Sampler1D    *Sampler1D    `xml:"sampler1D"`
+	Sampler2D    *Sampler2D    `xml:"sampler2D"`
+	Sampler3D    *Sampler3D    `xml:"sampler3D"`
+	SamplerCube  *SamplerCube  `xml:"samplerCUBE"`
+	SamplerRect  *SamplerRect  `xml:"samplerRECT"`
+	SamplerDepth *SamplerDepth `xml:"samplerDEPTH"`
+	//SidRef is a value of kinematics parameters.
+	SidRef *string `xml:"SIDREF"`
+	//TexturePipeline is a value of profile_GLES parameters.
+	TexturePipeline *TexturePipeline `xml:"texture_pipeline"`
+	//Usertype and Array are values of profile_CG parameters.
+	Usertype *Usertype `xml:"usertype"`
+	Array    *Array    `xml:"array"`
+}
+
+//Surface declares a resource that can be used as the source of a texture sampler in COLLADA 1.4.
+type Surface struct {
+	Type     string             `xml:"type,attr"`
+	InitFrom []*SurfaceInitFrom `xml:"init_from"`
+	Format   string             `xml:"format,omitempty"`
+	HasExtra
+}
+
+//SurfaceInitFrom names the image initializing a surface in COLLADA 1.4.
+type SurfaceInitFrom struct {
+	Mip   uint   `xml:"mip,attr,omitempty"`
+	Slice uint   `xml:"slice,attr,omitempty"`
+	Face  string `xml:"face,attr,omitempty"`
+	Value Id     `xml:",chardata"`
 }
 
 //ParamReference (reference) References a predefined parameter. See Chapter 5: Core Elements Reference.
 type ParamReference struct {
-	//TODO
+	Ref string `xml:"ref,attr"`
 }
 
 //SamplerStates Allows users to modify an effect’s sampler state from a material.
@@ -813,29 +1041,42 @@ type SamplerStates struct {
 
 //Semantic Provides metadata that describes the purpose of a parameter declaration.
 type Semantic struct {
-	//TODO
+	Value string `xml:",chardata"`
 }
 
 //Setparam Assigns a new value to a previously defined parameter. See main entry in Chapter 5: Core Elements Reference.
 type Setparam struct {
 	Ref string `xml:"ref,attr"`
+	ParamValue
 }
 
 //Usertype Creates an instance of a structured class for a parameter.
 type Usertype struct {
-	//TODO
+	Typename string      `xml:"typename,attr"`
+	Source   string      `xml:"source,attr,omitempty"`
+	Setparam []*Setparam `xml:"setparam"`
 }
 
 //ProfileBridge Provides support for referencing effect profiles written with external standards.
 type ProfileBridge struct {
 	HasId
+	Platform string `xml:"platform,attr,omitempty"`
+	HasUrl
 	HasAsset
 	HasNewparam
+	HasExtra
 }
 
 //ProfileCg Declares a platform-specific representation of an effect written in the NVIDIA®Cg language.
 type ProfileCg struct {
-	//TODO
+	HasId
+	Platform string `xml:"platform,attr,omitempty"`
+	HasAsset
+	Code    []*Code    `xml:"code"`
+	Include []*Include `xml:"include"`
+	HasNewparam
+	HasTechniqueFx
+	HasExtra
 }
 
 //ProfileCommon Opens a block of platform-independent declarations for the common, fixed-function shader.
@@ -846,23 +1087,59 @@ type ProfileCommon struct {
 	HasTechniqueFx
 	HasExtra
 }
+
+//ProfileGles declares a fixed-function pipeline effect for OpenGL ES 1.x, whose passes configure texture pipelines through their states.
 type ProfileGles struct {
-	//TODO
+	HasId
+	Platforms string `xml:"platforms,attr,omitempty"`
+	HasAsset
+	HasNewparam
+	HasTechniqueFx
+	HasExtra
 }
+
+//ProfileGles2 declares a programmable effect for OpenGL ES 2.0.
 type ProfileGles2 struct {
-	//TODO
+	HasId
+	Language  string `xml:"language,attr,omitempty"`
+	Platforms string `xml:"platforms,attr,omitempty"`
+	HasAsset
+	Code    []*Code    `xml:"code"`
+	Include []*Include `xml:"include"`
+	HasNewparam
+	HasTechniqueFx
+	HasExtra
 }
 type ProfileGlsl struct {
-	//TODO
+	HasId
+	Platform string `xml:"platform,attr,omitempty"`
+	HasAsset
+	Code    []*Code    `xml:"code"`
+	Include []*Include `xml:"include"`
+	HasNewparam
+	HasTechniqueFx
+	HasExtra
 }
+
+//Blinn Produces a shaded surface that reflects ambient, diffuse, and specular reflection, where the specular reflection is shaded according the Blinn BRDF approximation.
 type Blinn struct {
-	//TODO
+	Emission          *FxCommonColorOrTextureType `xml:"emission"`
+	AmbientFx         *FxCommonColorOrTextureType `xml:"ambient"`
+	Diffuse           *FxCommonColorOrTextureType `xml:"diffuse"`
+	Specular          *FxCommonColorOrTextureType `xml:"specular"`
+	Shininess         *FxCommonFloatOrParamType   `xml:"shininess"`
+	Reflective        *FxCommonColorOrTextureType `xml:"reflective"`
+	Reflectivity      *FxCommonFloatOrParamType   `xml:"reflectivity"`
+	Transparent       *FxCommonColorOrTextureType `xml:"transparent"`
+	Transparency      *FxCommonFloatOrParamType   `xml:"transparency"`
+	IndexOfRefraction *FxCommonFloatOrParamType   `xml:"index_of_refraction"`
 }
 type ColorClear struct {
-	//TODO
+	Index uint        `xml:"index,attr,omitempty"`
+	Value FloatValues `xml:",chardata"`
 }
 type ColorTarget struct {
-	//TODO
+	RenderTarget
 }
 
 // A type that describes color attributes of fixed-function shader elements
@@ -887,42 +1164,96 @@ type FxCommonFloatOrParamType struct {
 
 //Constant Produces a constantly shaded surface that is independent of lighting.
 type ConstantFx struct {
-	//TODO
+	Emission          *FxCommonColorOrTextureType `xml:"emission"`
+	Reflective        *FxCommonColorOrTextureType `xml:"reflective"`
+	Reflectivity      *FxCommonFloatOrParamType   `xml:"reflectivity"`
+	Transparent       *FxCommonColorOrTextureType `xml:"transparent"`
+	Transparency      *FxCommonFloatOrParamType   `xml:"transparency"`
+	IndexOfRefraction *FxCommonFloatOrParamType   `xml:"index_of_refraction"`
 }
 
 //DepthClear Specifies whether a render target image is to be cleared, and which value to use.
 type DepthClear struct {
-	//TODO
+	Index uint    `xml:"index,attr,omitempty"`
+	Value float64 `xml:",chardata"`
 }
 
 //DepthTarget Specifies which <image> will receive the depth information from the output of this pass.
 type DepthTarget struct {
-	//TODO
+	RenderTarget
 }
 
 //Draw Instructs the FX Runtime what kind of geometry to submit.
 type Draw struct {
-	//TODO
+	Value DrawKind `xml:",chardata"`
 }
 
+//DrawKind names the geometry a pass submits. Values other than the constants are application defined.
+type DrawKind string
+
+const (
+	DrawGeometry                    DrawKind = "GEOMETRY"
+	DrawSceneGeometry               DrawKind = "SCENE_GEOMETRY"
+	DrawSceneImage                  DrawKind = "SCENE_IMAGE"
+	DrawFullScreenQuad              DrawKind = "FULL_SCREEN_QUAD"
+	DrawFullScreenQuadPlusHalfPixel DrawKind = "FULL_SCREEN_QUAD_PLUS_HALF_PIXEL"
+)
+
 //Evaluate Contains evaluation elements for a rendering pass.
 type Evaluate struct {
-	//TODO
+	ColorTarget   []*ColorTarget   `xml:"color_target"`
+	DepthTarget   []*DepthTarget   `xml:"depth_target"`
+	StencilTarget []*StencilTarget `xml:"stencil_target"`
+	ColorClear    []*ColorClear    `xml:"color_clear"`
+	DepthClear    []*DepthClear    `xml:"depth_clear"`
+	StencilClear  []*StencilClear  `xml:"stencil_clear"`
+	Draw          *Draw            `xml:"draw"`
+}
+
+//RenderTarget specifies the image, given by a parameter or instantiated, which receives an output of a pass.
+type RenderTarget struct {
+	Index         uint            `xml:"index,attr,omitempty"`
+	Slice         uint            `xml:"slice,attr,omitempty"`
+	Mip           uint            `xml:"mip,attr,omitempty"`
+	Face          string          `xml:"face,attr,omitempty"`
+	Param         *ParamReference `xml:"param"`
+	InstanceImage *InstanceImage  `xml:"instance_image"`
 }
 
 //InstanceMaterialRendering Instantiates a COLLADA material resource for a screen effect.
 type InstanceMaterialRendering struct {
-	//TODO
+	HasUrl
+	TechniqueOverride *TechniqueOverride `xml:"technique_override"`
+	Bind              []*Bind            `xml:"bind"`
+	HasExtra
+}
+
+//TechniqueOverride selects the technique, and optionally the pass, of the effect used to render a scene.
+type TechniqueOverride struct {
+	Ref  string `xml:"ref,attr"`
+	Pass string `xml:"pass,attr,omitempty"`
 }
 
 //Lambert Produces a diffuse shaded surface that is independent of lighting.
 type Lambert struct {
-	//TODO
+	Emission          *FxCommonColorOrTextureType `xml:"emission"`
+	AmbientFx         *FxCommonColorOrTextureType `xml:"ambient"`
+	Diffuse           *FxCommonColorOrTextureType `xml:"diffuse"`
+	Reflective        *FxCommonColorOrTextureType `xml:"reflective"`
+	Reflectivity      *FxCommonFloatOrParamType   `xml:"reflectivity"`
+	Transparent       *FxCommonColorOrTextureType `xml:"transparent"`
+	Transparency      *FxCommonFloatOrParamType   `xml:"transparency"`
+	IndexOfRefraction *FxCommonFloatOrParamType   `xml:"index_of_refraction"`
 }
 
 //Pass Provides a static declaration of all the render states, shaders, and settings for one rendering pipeline.
 type Pass struct {
-	//TODO
+	HasSid
+	HasAnnotate
+	States   *States   `xml:"states"`
+	Program  *Program  `xml:"program"`
+	Evaluate *Evaluate `xml:"evaluate"`
+	HasExtra
 }
 
 //Phong Produces a shaded surface where the specular reflection is shaded
@@ -946,133 +1277,373 @@ type According struct {
 
 //Render Describes one effect pass to evaluate a scene.
 type Render struct {
-	//TODO
+	HasName
+	HasSid
+	CameraNode       Uri                        `xml:"camera_node,attr,omitempty"`
+	Layer            []string                   `xml:"layer"`
+	InstanceMaterial *InstanceMaterialRendering `xml:"instance_material"`
+	HasExtra
 }
 
 //States Contains all rendering states to set up for the parent pass.
 type States struct {
-	//TODO
+	XML string `xml:",innerxml"`
 }
 
 //StencilClear Specifies whether a render target image is to be cleared, and which value to use.
 type StencilClear struct {
-	//TODO
+	Index uint  `xml:"index,attr,omitempty"`
+	Value uint8 `xml:",chardata"`
 }
 
 //StencilTarget Specifies which <image> will receive the stencil information from the output of this pass
 type StencilTarget struct {
-	//TODO
+	RenderTarget
 }
 
 //Binary Identifies or provides a shader in binary form.
 type Binary struct {
-	//TODO
+	Ref Uri  `xml:"ref,omitempty"`
+	Hex *Hex `xml:"hex"`
 }
 
 //BindAttribute Binds semantics to vertex attribute inputs of a shader.
 type BindAttribute struct {
-	//TODO
+	Symbol   string `xml:"symbol,attr"`
+	Semantic string `xml:"semantic"`
 }
 
 //BindUniform Binds values to uniform inputs of a shader or binds values to effect parameters upon instantiation.
 type BindUniform struct {
-	//TODO
+	Symbol string          `xml:"symbol,attr"`
+	Param  *ParamReference `xml:"param"`
+	ParamValue
 }
 
 //Code Provides an inline block of source code.
 type Code struct {
-	//TODO
+	HasSid
+	Value string `xml:",chardata"`
 }
 
 //Compiler Contains command-line or runtime-invocation options for a shader compiler.
 type Compiler struct {
-	//TODO
+	Platform string  `xml:"platform,attr"`
+	Target   string  `xml:"target,attr,omitempty"`
+	Options  string  `xml:"options,attr,omitempty"`
+	Binary   *Binary `xml:"binary"`
 }
 
 //Include Imports source code or precompiled binary shaders into the FX Runtime by referencing an external resource.
 type Include struct {
-	//TODO
+	HasSid
+	HasUrl
 }
 
 //Linker Contains command-line or runtime-invocation options for shader linkers to combine shaders into programs.
 type Linker struct {
-	//TODO
+	Platform string    `xml:"platform,attr"`
+	Target   string    `xml:"target,attr,omitempty"`
+	Options  string    `xml:"options,attr,omitempty"`
+	Binary   []*Binary `xml:"binary"`
 }
 
 //Program Links multiple shaders together to produce a pipeline for geometry processing.
 type Program struct {
-	//TODO
+	Shader        []*Shader        `xml:"shader"`
+	Linker        []*Linker        `xml:"linker"`
+	BindAttribute []*BindAttribute `xml:"bind_attribute"`
+	BindUniform   []*BindUniform   `xml:"bind_uniform"`
 }
+
+//ShaderStage names the pipeline stage a shader runs in.
+type ShaderStage string
+
+const (
+	StageTessellation ShaderStage = "TESSELLATION"
+	StageVertex       ShaderStage = "VERTEX"
+	StageGeometry     ShaderStage = "GEOMETRY"
+	StageFragment     ShaderStage = "FRAGMENT"
+)
+
+//Shader declares and prepares a shader for execution in the rendering pipeline of a pass.
 type Shader struct {
-	//TODO
+	Stage    ShaderStage `xml:"stage,attr"`
+	Sources  Sources     `xml:"sources"`
+	Compiler []*Compiler `xml:"compiler"`
+	//BindUniform binds the uniforms of a profile_CG shader; other profiles bind them in the program.
+	BindUniform []*BindUniform `xml:"bind_uniform"`
+	HasExtra
 }
+
+//Sources concatenates inline source code and imported <code> or <include> blocks, in order, into the source of a shader.
 type Sources struct {
-	//TODO
+	Entry  string
+	Source []*ShaderSource
+}
+
+//ShaderSource is either a block of inline code or the sid of an imported <code> or <include>.
+type ShaderSource struct {
+	Inline string
+	Import string
 }
+
+//Alpha defines the alpha portion of a texture combiner stage.
 type Alpha struct {
-	//TODO
+	Operator string      `xml:"operator,attr,omitempty"`
+	Scale    *float64    `xml:"scale,attr"`
+	Argument []*Argument `xml:"argument"`
 }
+
+//Argument defines an argument of the RGB or alpha component of a texture combiner stage.
 type Argument struct {
-	//TODO
+	Source  string `xml:"source,attr,omitempty"`
+	Operand string `xml:"operand,attr,omitempty"`
+	Sampler string `xml:"sampler,attr,omitempty"`
 }
+
+//Create2d initializes a custom 2D image, optionally with mipmaps and array slices.
 type Create2d struct {
-	//TODO
+	SizeExact    *SizeExact    `xml:"size_exact"`
+	SizeRatio    *SizeRatio    `xml:"size_ratio"`
+	Mips         *Mips         `xml:"mips"`
+	Unnormalized *Unnormalized `xml:"unnormalized"`
+	Array        *ImageArray   `xml:"array"`
+	Format       *Format       `xml:"format"`
+	InitFrom     []*InitFrom   `xml:"init_from"`
 }
+
+//Create3d initializes a custom 3D image.
 type Create3d struct {
-	//TODO
+	Size     ImageSize   `xml:"size"`
+	Mips     Mips        `xml:"mips"`
+	Array    *ImageArray `xml:"array"`
+	Format   *Format     `xml:"format"`
+	InitFrom []*InitFrom `xml:"init_from"`
 }
+
+//CreateCube initializes a custom cube map image.
 type CreateCube struct {
-	//TODO
-}
-type Format struct {
-	//TODO
+	Size     ImageSize   `xml:"size"`
+	Mips     Mips        `xml:"mips"`
+	Array    *ImageArray `xml:"array"`
+	Format   *Format     `xml:"format"`
+	InitFrom []*InitFrom `xml:"init_from"`
 }
-type Image struct {
-	//TODO
+
+//SizeExact specifies the dimensions of a 2D image in texels.
+type SizeExact struct {
+	Width  uint `xml:"width,attr"`
+	Height uint `xml:"height,attr"`
 }
-type InitFrom struct {
-	//TODO
+
+//SizeRatio specifies the dimensions of a 2D image relative to the viewport.
+type SizeRatio struct {
+	Width  float64 `xml:"width,attr"`
+	Height float64 `xml:"height,attr"`
 }
-type InstanceImage struct {
-	//TODO
+
+//ImageSize specifies the dimensions of a 3D or cube map image; cube maps only use Width.
+type ImageSize struct {
+	Width  uint `xml:"width,attr"`
+	Height uint `xml:"height,attr,omitempty"`
+	Depth  uint `xml:"depth,attr,omitempty"`
 }
-type LibraryImages struct {
-	//TODO
+
+//Mips specifies the number of mipmap levels of an image, zero meaning a full chain.
+type Mips struct {
+	Levels       uint  `xml:"levels,attr"`
+	AutoGenerate *bool `xml:"auto_generate,attr"`
 }
-type Rgb struct {
-	//TODO
+
+//Unnormalized marks a 2D image addressed by texel coordinates rather than normalized ones.
+type Unnormalized struct{}
+
+//ImageArray specifies the number of array slices of an image.
+type ImageArray struct {
+	Length uint `xml:"length,attr"`
 }
-type FxSamplerCommon struct {
-	//TODO
+
+//Format describes the texel format of an image, either as hints or as an exact platform format.
+type Format struct {
+	Hint  *FormatHint `xml:"hint"`
+	Exact string      `xml:"exact,omitempty"`
 }
-type Sampler1D struct {
-	//TODO
+
+//FormatHint describes the channels, range, precision and color space of a texel format.
+type FormatHint struct {
+	Channels  string `xml:"channels,attr"`
+	Range     string `xml:"range,attr"`
+	Precision string `xml:"precision,attr,omitempty"`
+	Space     string `xml:"space,attr,omitempty"`
 }
-type Sampler2D struct {
-	//TODO
+
+//Image declares the storage for the graphical representation of an object.
+type Image struct {
+	HasId
+	HasSid
+	HasName
+	HasAsset
+	Renderable *Renderable `xml:"renderable"`
+	InitFrom   *InitFrom   `xml:"init_from"`
+	Create2d   *Create2d   `xml:"create_2d"`
+	Create3d   *Create3d   `xml:"create_3d"`
+	CreateCube *CreateCube `xml:"create_cube"`
+	HasExtra
+}
+
+//Renderable declares that an image can be used as a render target.
+type Renderable struct {
+	Share bool `xml:"share,attr"`
+}
+
+//InitFrom initializes an image, or a face, depth slice, mip level or array slice of it, from an external
+//resource or from embedded data. COLLADA 1.4 documents give the uri as the content of the element instead of a <ref>.
+type InitFrom struct {
+	MipsGenerate *bool  `xml:"mips_generate,attr"`
+	ArrayIndex   uint   `xml:"array_index,attr,omitempty"`
+	MipIndex     uint   `xml:"mip_index,attr,omitempty"`
+	Depth        uint   `xml:"depth,attr,omitempty"`
+	Face         string `xml:"face,attr,omitempty"`
+	Ref          Uri    `xml:"ref,omitempty"`
+	Hex          *Hex   `xml:"hex"`
+	Value        Uri    `xml:",chardata"`
+}
+
+//Hex holds image data embedded in the document as hexadecimal text.
+type Hex struct {
+	Format string    `xml:"format,attr"`
+	V      HexBinary `xml:",chardata"`
+}
+
+//InstanceImage instantiates a COLLADA image resource.
+type InstanceImage struct {
+	HasSid
+	HasName
+	HasUrl
+	HasExtra
+}
+
+//LibraryImages provides a library in which to place <image> elements.
+type LibraryImages struct {
+	HasId
+	HasName
+	HasAsset
+	Image []*Image `xml:"image"`
+	HasExtra
+}
+
+//Rgb defines the RGB portion of a texture combiner stage.
+type Rgb struct {
+	Operator string      `xml:"operator,attr,omitempty"`
+	Scale    *float64    `xml:"scale,attr"`
+	Argument []*Argument `xml:"argument"`
+}
+
+//WrapMode specifies how a sampler treats texture coordinates outside of [0,1].
+type WrapMode string
+
+const (
+	WrapWrap        WrapMode = "WRAP"
+	WrapMirror      WrapMode = "MIRROR"
+	WrapClamp       WrapMode = "CLAMP"
+	WrapBorder      WrapMode = "BORDER"
+	WrapMirrorOnce  WrapMode = "MIRROR_ONCE"
+	WrapNone        WrapMode = "NONE"
+	WrapRepeat      WrapMode = "REPEAT"
+	WrapClampToEdge WrapMode = "CLAMP_TO_EDGE"
+)
+
+//FilterMode specifies how a sampler filters texels when minifying, magnifying or between mip levels.
+type FilterMode string
+
+const (
+	FilterNone                 FilterMode = "NONE"
+	FilterNearest              FilterMode = "NEAREST"
+	FilterLinear               FilterMode = "LINEAR"
+	FilterAnisotropic          FilterMode = "ANISOTROPIC"
+	FilterNearestMipmapNearest FilterMode = "NEAREST_MIPMAP_NEAREST"
+	FilterLinearMipmapNearest  FilterMode = "LINEAR_MIPMAP_NEAREST"
+	FilterNearestMipmapLinear  FilterMode = "NEAREST_MIPMAP_LINEAR"
+	FilterLinearMipmapLinear   FilterMode = "LINEAR_MIPMAP_LINEAR"
+)
+
+//FxSamplerCommon declares the image and states of a texture sampler.
+//A COLLADA 1.5 sampler instantiates its image, while a 1.4 sampler names the sid of a <surface> parameter as its Source.
+type FxSamplerCommon struct {
+	Source         string           `xml:"source,omitempty"`
+	InstanceImage  *InstanceImage   `xml:"instance_image"`
+	Texcoord       *SamplerTexcoord `xml:"texcoord"`
+	WrapS          WrapMode         `xml:"wrap_s,omitempty"`
+	WrapT          WrapMode         `xml:"wrap_t,omitempty"`
+	WrapP          WrapMode         `xml:"wrap_p,omitempty"`
+	Minfilter      FilterMode       `xml:"minfilter,omitempty"`
+	Magfilter      FilterMode       `xml:"magfilter,omitempty"`
+	Mipfilter      FilterMode       `xml:"mipfilter,omitempty"`
+	BorderColor    *Floats          `xml:"border_color"`
+	MipMaxLevel    *uint            `xml:"mip_max_level"`
+	MipMinLevel    *uint            `xml:"mip_min_level"`
+	MipBias        *float64         `xml:"mip_bias"`
+	MaxAnisotropy  *uint            `xml:"max_anisotropy"`
+	MipmapMaxlevel *uint            `xml:"mipmap_maxlevel"`
+	MipmapBias     *float64         `xml:"mipmap_bias"`
+	HasExtra
+}
+
+//SamplerTexcoord names the semantic of the texture coordinates a sampler expects.
+type SamplerTexcoord struct {
+	Semantic string `xml:"semantic,attr"`
+}
+type Sampler1D struct {
+	FxSamplerCommon
+}
+type Sampler2D struct {
+	FxSamplerCommon
 }
 type Sampler3D struct {
-	//TODO
+	FxSamplerCommon
 }
 type SamplerCube struct {
-	//TODO
+	FxSamplerCommon
 }
 type SamplerDepth struct {
-	//TODO
+	FxSamplerCommon
 }
 type SamplerRect struct {
-	//TODO
+	FxSamplerCommon
 }
+
+//Texcombiner defines a texture unit setup in combiner mode.
 type Texcombiner struct {
-	//TODO
+	Constant *TexturePipelineConstant `xml:"constant"`
+	Rgb      *Rgb                     `xml:"RGB"`
+	Alpha    *Alpha                   `xml:"alpha"`
 }
+
+//Texenv defines a texture unit setup in environment mode.
 type Texenv struct {
-	//TODO
+	Operator string                   `xml:"operator,attr,omitempty"`
+	Sampler  string                   `xml:"sampler,attr,omitempty"`
+	Constant *TexturePipelineConstant `xml:"constant"`
+}
+
+//TexturePipelineConstant sets the constant color of a texture unit, given directly or by a parameter.
+type TexturePipelineConstant struct {
+	Value FloatValues `xml:"value,attr,omitempty"`
+	Param string      `xml:"param,attr,omitempty"`
 }
 
 //TexturePipeline Defines a set of texturing commands that will be converted into multitexturing operations using glTexEnv in regular and combiner mode.
 type TexturePipeline struct {
-	//TODO
+	HasSid
+	Stage []*TextureStage
+	HasExtra
+}
+
+//TextureStage configures one texture unit of a pipeline. Exactly one field is set.
+type TextureStage struct {
+	Texcombiner *Texcombiner
+	Texenv      *Texenv
 }
 
 type P struct {
@@ -1080,29 +1651,25 @@ type P struct {
 }
 
 type Floats struct {
-    Values
+	V FloatValues `xml:",chardata"`
 }
 type Bools struct {
-    Values
+	V BoolValues `xml:",chardata"`
 }
 type IdRefs struct {
-    Values
+	V StringValues `xml:",chardata"`
 }
 type Ints struct {
-    Values
+	V IntValues `xml:",chardata"`
 }
 type Names struct {
-    Values
+	V StringValues `xml:",chardata"`
 }
 type SidRefs struct {
-    Values
+	V StringValues `xml:",chardata"`
 }
 type Tokens struct {
-    Values
-}
-
-type Values struct {
-	V string `xml:",chardata"`
+	V StringValues `xml:",chardata"`
 }
 
 type Float3x3 struct {
@@ -1178,6 +1745,13 @@ type HasPs struct {
 	P []*P `xml:"p"`
 }
 
+//LoadOptions configures how a document is decoded by LoadDocumentWithOptions.
+type LoadOptions struct {
+	//Workers is the number of goroutines used to convert the text of all float and int arrays, including
+	//vcounts, indices and parameter values, once the xml has been decoded. Values less than one use runtime.GOMAXPROCS.
+	Workers int
+}
+
 func LoadDocument(filename string) (*Collada, error) {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -1198,20 +1772,671 @@ func LoadDocumentFromReader(reader io.Reader) (*Collada, error) {
 	return collada, nil
 }
 
+func LoadDocumentWithOptions(filename string, options LoadOptions) (*Collada, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	collada, err := LoadDocumentFromReaderWithOptions(file, options)
+	return collada, err
+}
+
+func LoadDocumentFromReaderWithOptions(reader io.Reader, options LoadOptions) (*Collada, error) {
+	workers := options.Workers
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	arrays := &arrayDecoder{d: xml.NewDecoder(reader)}
+	collada := &Collada{}
+	err := arrays.decodeDocument(collada)
+	if err != nil {
+		return nil, err
+	}
+	err = arrays.run(workers)
+	if err != nil {
+		return nil, err
+	}
+	return collada, nil
+}
+
+//ExportOptions configures ExportWithOptions.
+type ExportOptions struct {
+	//FloatFormat is the format of the floating-point values written as element text or as lists in attributes.
+	FloatFormat FloatFormat
+}
+
 func (collada *Collada) Export(filename string) error {
+	return collada.ExportWithOptions(filename, ExportOptions{})
+}
+
+func (collada *Collada) ExportToWriter(writer io.Writer) error {
+	return collada.ExportToWriterWithOptions(writer, ExportOptions{})
+}
+
+func (collada *Collada) ExportWithOptions(filename string, options ExportOptions) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
-	return collada.ExportToWriter(file)
+	return collada.ExportToWriterWithOptions(file, options)
 }
 
-func (collada *Collada) ExportToWriter(writer io.Writer) error {
+func (collada *Collada) ExportToWriterWithOptions(writer io.Writer, options ExportOptions) error {
 	w := bufio.NewWriter(writer)
 	w.WriteString(xml.Header)
 	w.Flush()
 	encoder := xml.NewEncoder(writer)
 	encoder.Indent("", " ")
+	if options.FloatFormat != (FloatFormat{}) {
+		floats := &floatEncoder{e: encoder, format: options.FloatFormat}
+		if err := floats.encode(collada, xml.StartElement{Name: xml.Name{Local: "COLLADA"}}); err != nil {
+			return err
+		}
+		return encoder.Flush()
+	}
 	return encoder.Encode(collada)
 }
+
+//LibraryJoints provides a library in which to place <joint> elements.
+type LibraryJoints struct {
+	HasId
+	HasName
+	HasAsset
+	Joint []*Joint `xml:"joint"`
+	HasExtra
+}
+
+//Joint defines a single joint, made of one or more prismatic or revolute degrees of freedom.
+type Joint struct {
+	HasId
+	HasName
+	HasSid
+	//Axis holds the prismatic and revolute axes of the joint in document order.
+	Axis []*JointAxis `xml:",any"`
+	HasExtra
+}
+
+//JointKind names the motion of a joint axis.
+type JointKind string
+
+const (
+	JointPrismatic JointKind = "prismatic"
+	JointRevolute  JointKind = "revolute"
+)
+
+//JointAxis is a degree of freedom of a joint, translating along or rotating about its axis.
+//Limits are in the units of the document for prismatic axes and in degrees for revolute ones.
+type JointAxis struct {
+	//Kind is the element declaring the axis, empty for elements of other kinds decoded in its place.
+	Kind JointKind `xml:"-"`
+	HasSid
+	Axis   Axis         `xml:"axis"`
+	Limits *JointLimits `xml:"limits"`
+}
+
+//Axis is the direction of a joint axis.
+type Axis struct {
+	HasSid
+	Float3
+}
+
+//JointLimits bounds the value of a joint axis.
+type JointLimits struct {
+	Min *Minmax `xml:"min"`
+	Max *Minmax `xml:"max"`
+}
+
+//Minmax is a limit of a joint axis.
+type Minmax struct {
+	HasName
+	HasSid
+	Value float64 `xml:",chardata"`
+}
+
+//InstanceJoint instantiates a joint.
+type InstanceJoint struct {
+	HasSid
+	HasName
+	HasUrl
+	HasExtra
+}
+
+//LibraryKinematicsModels provides a library in which to place <kinematics_model> elements.
+type LibraryKinematicsModels struct {
+	HasId
+	HasName
+	HasAsset
+	KinematicsModel []*KinematicsModel `xml:"kinematics_model"`
+	HasExtra
+}
+
+//KinematicsModel describes a kinematic chain of links connected by joints.
+type KinematicsModel struct {
+	HasId
+	HasName
+	HasAsset
+	TechniqueCommon KinematicsModelTechniqueCommon `xml:"technique_common"`
+	HasTechnique
+	HasExtra
+}
+
+//KinematicsModelTechniqueCommon declares the joints and links of a kinematics model.
+type KinematicsModelTechniqueCommon struct {
+	HasNewparam
+	InstanceJoint []*InstanceJoint `xml:"instance_joint"`
+	Joint         []*Joint         `xml:"joint"`
+	Link          []*Link          `xml:"link"`
+}
+
+//Link is a rigid body of a kinematic chain, placed by its transforms and carrying the joints attached to it.
+type Link struct {
+	HasSid
+	HasName
+	Transform       Transforms        `xml:",any"`
+	AttachmentFull  []*AttachmentFull `xml:"attachment_full"`
+	AttachmentStart []*Attachment     `xml:"attachment_start"`
+	AttachmentEnd   []*Attachment     `xml:"attachment_end"`
+}
+
+//AttachmentFull connects a joint to the link it moves, which is placed by the transforms of the attachment.
+type AttachmentFull struct {
+	Joint     string     `xml:"joint,attr"`
+	Transform Transforms `xml:",any"`
+	Link      *Link      `xml:"link"`
+}
+
+//Attachment connects one end of a joint to a link, closing a kinematic loop.
+type Attachment struct {
+	Joint     string     `xml:"joint,attr"`
+	Transform Transforms `xml:",any"`
+}
+
+//LibraryArticulatedSystems provides a library in which to place <articulated_system> elements.
+type LibraryArticulatedSystems struct {
+	HasId
+	HasName
+	HasAsset
+	ArticulatedSystem []*ArticulatedSystem `xml:"articulated_system"`
+	HasExtra
+}
+
+//ArticulatedSystem adds kinematic or dynamic information to a kinematics model or another articulated system.
+//Exactly one of Kinematics and Motion is set.
+type ArticulatedSystem struct {
+	HasId
+	HasName
+	HasAsset
+	Kinematics *Kinematics `xml:"kinematics"`
+	Motion     *Motion     `xml:"motion"`
+	HasExtra
+}
+
+//Kinematics describes the kinematic behavior of the axes of kinematics models.
+type Kinematics struct {
+	InstanceKinematicsModel []*InstanceKinematicsModel `xml:"instance_kinematics_model"`
+	TechniqueCommon         KinematicsTechniqueCommon  `xml:"technique_common"`
+	HasTechnique
+	HasExtra
+}
+
+//KinematicsTechniqueCommon describes the axes and frames of an articulated system.
+type KinematicsTechniqueCommon struct {
+	AxisInfo    []*KinematicsAxisInfo `xml:"axis_info"`
+	FrameOrigin Frame                 `xml:"frame_origin"`
+	FrameTip    Frame                 `xml:"frame_tip"`
+	FrameTcp    *Frame                `xml:"frame_tcp"`
+	FrameObject *Frame                `xml:"frame_object"`
+}
+
+//KinematicsAxisInfo describes the kinematic behavior of a joint axis, given by the sid reference Axis.
+type KinematicsAxisInfo struct {
+	HasSid
+	HasName
+	Axis string `xml:"axis,attr"`
+	HasNewparam
+	Active *CommonBoolOrParam `xml:"active"`
+	Locked *CommonBoolOrParam `xml:"locked"`
+	Index  []*KinematicsIndex `xml:"index"`
+	Limits *KinematicsLimits  `xml:"limits"`
+}
+
+//KinematicsIndex numbers an axis for the semantic of an application.
+type KinematicsIndex struct {
+	Semantic string `xml:"semantic,attr,omitempty"`
+	CommonIntOrParam
+}
+
+//KinematicsLimits overrides the limits of a joint axis.
+type KinematicsLimits struct {
+	Min *CommonFloatOrParam `xml:"min"`
+	Max *CommonFloatOrParam `xml:"max"`
+}
+
+//Frame places a frame of an articulated system relative to a link.
+type Frame struct {
+	Link      string     `xml:"link,attr,omitempty"`
+	Transform Transforms `xml:",any"`
+}
+
+//Motion describes the dynamics of an articulated system.
+type Motion struct {
+	InstanceArticulatedSystem InstanceArticulatedSystem `xml:"instance_articulated_system"`
+	TechniqueCommon           MotionTechniqueCommon     `xml:"technique_common"`
+	HasTechnique
+	HasExtra
+}
+
+//MotionTechniqueCommon describes the dynamics of the axes and end effector of an articulated system.
+type MotionTechniqueCommon struct {
+	AxisInfo     []*MotionAxisInfo `xml:"axis_info"`
+	EffectorInfo *EffectorInfo     `xml:"effector_info"`
+}
+
+//MotionAxisInfo bounds the motion of an axis of the kinematics an articulated system instantiates.
+type MotionAxisInfo struct {
+	HasSid
+	HasName
+	Axis string            `xml:"axis,attr"`
+	Bind []*KinematicsBind `xml:"bind"`
+	HasNewparam
+	Setparam     []*Setparam         `xml:"setparam"`
+	Speed        *CommonFloatOrParam `xml:"speed"`
+	Acceleration *CommonFloatOrParam `xml:"acceleration"`
+	Deceleration *CommonFloatOrParam `xml:"deceleration"`
+	Jerk         *CommonFloatOrParam `xml:"jerk"`
+}
+
+//EffectorInfo bounds the motion of the end effector of an articulated system,
+//with the translational and rotational components of each bound given as a float2.
+type EffectorInfo struct {
+	HasSid
+	HasName
+	Bind []*KinematicsBind `xml:"bind"`
+	HasNewparam
+	Setparam     []*Setparam          `xml:"setparam"`
+	Speed        *CommonFloat2OrParam `xml:"speed"`
+	Acceleration *CommonFloat2OrParam `xml:"acceleration"`
+	Deceleration *CommonFloat2OrParam `xml:"deceleration"`
+	Jerk         *CommonFloat2OrParam `xml:"jerk"`
+}
+
+//InstanceKinematicsModel instantiates a kinematics model.
+type InstanceKinematicsModel struct {
+	HasSid
+	HasName
+	HasUrl
+	Bind []*KinematicsBind `xml:"bind"`
+	HasNewparam
+	Setparam []*Setparam `xml:"setparam"`
+	HasExtra
+}
+
+//InstanceArticulatedSystem instantiates an articulated system.
+type InstanceArticulatedSystem struct {
+	HasSid
+	HasName
+	HasUrl
+	Bind     []*KinematicsBind `xml:"bind"`
+	Setparam []*Setparam       `xml:"setparam"`
+	HasNewparam
+	HasExtra
+}
+
+//KinematicsBind binds a parameter of an instantiated kinematics element, here named by Symbol, to a value or another parameter.
+type KinematicsBind struct {
+	Symbol string          `xml:"symbol,attr"`
+	Param  *ParamReference `xml:"param"`
+	Bool   *bool           `xml:"bool"`
+	Float  *float64        `xml:"float"`
+	Int    *int            `xml:"int"`
+	SidRef string          `xml:"SIDREF,omitempty"`
+}
+
+//CommonBoolOrParam is a boolean given directly or by the sid of a parameter.
+type CommonBoolOrParam struct {
+	Bool  *bool  `xml:"bool"`
+	Param string `xml:"param,omitempty"`
+}
+
+//CommonIntOrParam is an integer given directly or by the sid of a parameter.
+type CommonIntOrParam struct {
+	Int   *int   `xml:"int"`
+	Param string `xml:"param,omitempty"`
+}
+
+//CommonFloatOrParam is a float given directly or by the sid of a parameter.
+type CommonFloatOrParam struct {
+	Float *float64 `xml:"float"`
+	Param string   `xml:"param,omitempty"`
+}
+
+//CommonFloat2OrParam is a pair of floats given directly or by the sid of a parameter.
+type CommonFloat2OrParam struct {
+	Float2 *Floats `xml:"float2"`
+	Param  string  `xml:"param,omitempty"`
+}
+
+//CommonSidrefOrParam is a sid reference given directly or by the sid of a parameter.
+type CommonSidrefOrParam struct {
+	SidRef string `xml:"SIDREF,omitempty"`
+	Param  string `xml:"param,omitempty"`
+}
+
+//LibraryKinematicsScenes provides a library in which to place <kinematics_scene> elements.
+type LibraryKinematicsScenes struct {
+	HasId
+	HasName
+	HasAsset
+	KinematicsScene []*KinematicsScene `xml:"kinematics_scene"`
+	HasExtra
+}
+
+//KinematicsScene embodies the kinematics models and articulated systems of a scene.
+type KinematicsScene struct {
+	HasId
+	HasName
+	HasAsset
+	InstanceKinematicsModel   []*InstanceKinematicsModel   `xml:"instance_kinematics_model"`
+	InstanceArticulatedSystem []*InstanceArticulatedSystem `xml:"instance_articulated_system"`
+	HasExtra
+}
+
+//BindKinematicsModel binds a kinematics model, given by a parameter or sid reference, to a node of the visual scene.
+type BindKinematicsModel struct {
+	Node string `xml:"node,attr"`
+	CommonSidrefOrParam
+}
+
+//BindJointAxis binds a joint axis of a kinematics scene to a transform of the visual scene, named by Target.
+type BindJointAxis struct {
+	Target string              `xml:"target,attr"`
+	Axis   CommonSidrefOrParam `xml:"axis"`
+	Value  CommonFloatOrParam  `xml:"value"`
+}
+
+//Bool is a boolean that may be targeted by a sid.
+type Bool struct {
+	HasSid
+	Value bool `xml:",chardata"`
+}
+
+//SidFloat3 is a float3 that may be targeted by a sid.
+type SidFloat3 struct {
+	HasSid
+	Float3
+}
+
+//LibraryForceFields provides a library in which to place <force_field> elements.
+type LibraryForceFields struct {
+	HasId
+	HasName
+	HasAsset
+	ForceField []*ForceField `xml:"force_field"`
+	HasExtra
+}
+
+//ForceField declares a force field, whose behavior is given entirely by profile-specific techniques.
+type ForceField struct {
+	HasId
+	HasName
+	HasAsset
+	HasTechnique
+	HasExtra
+}
+
+//InstanceForceField instantiates a force field.
+type InstanceForceField struct {
+	HasSid
+	HasName
+	HasUrl
+	HasExtra
+}
+
+//LibraryPhysicsMaterials provides a library in which to place <physics_material> elements.
+type LibraryPhysicsMaterials struct {
+	HasId
+	HasName
+	HasAsset
+	PhysicsMaterial []*PhysicsMaterial `xml:"physics_material"`
+	HasExtra
+}
+
+//PhysicsMaterial defines the friction and restitution of a rigid body or shape.
+type PhysicsMaterial struct {
+	HasId
+	HasName
+	HasAsset
+	TechniqueCommon PhysicsMaterialTechniqueCommon `xml:"technique_common"`
+	HasTechnique
+	HasExtra
+}
+
+//PhysicsMaterialTechniqueCommon holds the properties of a physics material for the common profile.
+type PhysicsMaterialTechniqueCommon struct {
+	DynamicFriction *Float `xml:"dynamic_friction"`
+	Restitution     *Float `xml:"restitution"`
+	StaticFriction  *Float `xml:"static_friction"`
+}
+
+//InstancePhysicsMaterial instantiates a physics material.
+type InstancePhysicsMaterial struct {
+	HasSid
+	HasName
+	HasUrl
+	HasExtra
+}
+
+//LibraryPhysicsModels provides a library in which to place <physics_model> elements.
+type LibraryPhysicsModels struct {
+	HasId
+	HasName
+	HasAsset
+	PhysicsModel []*PhysicsModel `xml:"physics_model"`
+	HasExtra
+}
+
+//PhysicsModel groups rigid bodies, the constraints between them and instances of other physics models.
+type PhysicsModel struct {
+	HasId
+	HasName
+	HasAsset
+	RigidBody            []*RigidBody            `xml:"rigid_body"`
+	RigidConstraint      []*RigidConstraint      `xml:"rigid_constraint"`
+	InstancePhysicsModel []*InstancePhysicsModel `xml:"instance_physics_model"`
+	HasExtra
+}
+
+//RigidBody declares a body whose shapes move together as one.
+type RigidBody struct {
+	HasId
+	HasSid
+	HasName
+	TechniqueCommon RigidBodyTechniqueCommon `xml:"technique_common"`
+	HasTechnique
+	HasExtra
+}
+
+//RigidBodyTechniqueCommon holds the properties of a rigid body for the common profile.
+//A body without Dynamic set to false is moved by the simulation.
+type RigidBodyTechniqueCommon struct {
+	Dynamic                 *Bool                    `xml:"dynamic"`
+	Mass                    *Float                   `xml:"mass"`
+	MassFrame               *MassFrame               `xml:"mass_frame"`
+	Inertia                 *SidFloat3               `xml:"inertia"`
+	InstancePhysicsMaterial *InstancePhysicsMaterial `xml:"instance_physics_material"`
+	PhysicsMaterial         *PhysicsMaterial         `xml:"physics_material"`
+	Shape                   []*Shape                 `xml:"shape"`
+}
+
+//MassFrame places the center and principal axes of inertia of a rigid body.
+type MassFrame struct {
+	Transform Transforms `xml:",any"`
+}
+
+//Shape is a collision shape of a rigid body, either an analytical shape or an instance of a geometry,
+//typically a <convex_mesh> or <mesh>. Exactly one of InstanceGeometry, Plane, Box, Sphere, Cylinder and Capsule is set.
+type Shape struct {
+	Hollow                  *Bool                    `xml:"hollow"`
+	Mass                    *Float                   `xml:"mass"`
+	Density                 *Float                   `xml:"density"`
+	InstancePhysicsMaterial *InstancePhysicsMaterial `xml:"instance_physics_material"`
+	PhysicsMaterial         *PhysicsMaterial         `xml:"physics_material"`
+	InstanceGeometry        *InstanceGeometry        `xml:"instance_geometry"`
+	Plane                   *Plane                   `xml:"plane"`
+	Box                     *Box                     `xml:"box"`
+	Sphere                  *Sphere                  `xml:"sphere"`
+	Cylinder                *Cylinder                `xml:"cylinder"`
+	Capsule                 *Capsule                 `xml:"capsule"`
+	Transform               Transforms               `xml:",any"`
+	HasExtra
+}
+
+//Plane is an infinite plane given by the coefficients of its equation Ax + By + Cz + D = 0.
+type Plane struct {
+	Equation Float4 `xml:"equation"`
+	HasExtra
+}
+
+//Box is an axis-aligned box centered on the origin.
+type Box struct {
+	HalfExtents Float3 `xml:"half_extents"`
+	HasExtra
+}
+
+//Sphere is a sphere centered on the origin.
+type Sphere struct {
+	Radius float64 `xml:"radius"`
+	HasExtra
+}
+
+//Cylinder is a cylinder along the y axis centered on the origin, with an elliptical cross-section given by two radii.
+type Cylinder struct {
+	Height float64 `xml:"height"`
+	Radius Floats  `xml:"radius"`
+	HasExtra
+}
+
+//Capsule is a cylinder along the y axis capped by hemi-ellipsoids, with the radii given as for Cylinder.
+type Capsule struct {
+	Height float64 `xml:"height"`
+	Radius Floats  `xml:"radius"`
+	HasExtra
+}
+
+//RigidConstraint connects two rigid bodies, limiting their relative motion.
+type RigidConstraint struct {
+	HasSid
+	HasName
+	RefAttachment   ConstraintAttachment           `xml:"ref_attachment"`
+	Attachment      ConstraintAttachment           `xml:"attachment"`
+	TechniqueCommon RigidConstraintTechniqueCommon `xml:"technique_common"`
+	HasTechnique
+	HasExtra
+}
+
+//ConstraintAttachment places the frame of a constraint relative to one of its rigid bodies.
+type ConstraintAttachment struct {
+	RigidBody string     `xml:"rigid_body,attr"`
+	Transform Transforms `xml:",any"`
+	HasExtra
+}
+
+//RigidConstraintTechniqueCommon holds the properties of a rigid constraint for the common profile.
+type RigidConstraintTechniqueCommon struct {
+	Enabled        *Bool             `xml:"enabled"`
+	Interpenetrate *Bool             `xml:"interpenetrate"`
+	Limits         *ConstraintLimits `xml:"limits"`
+	Spring         *ConstraintSpring `xml:"spring"`
+}
+
+//ConstraintLimits bounds the rotation, in degrees, and translation of the attachment relative to the reference attachment.
+type ConstraintLimits struct {
+	SwingConeAndTwist *ConstraintRange `xml:"swing_cone_and_twist"`
+	Linear            *ConstraintRange `xml:"linear"`
+}
+
+//ConstraintRange bounds each of the three axes of a constraint.
+type ConstraintRange struct {
+	Min *SidFloat3 `xml:"min"`
+	Max *SidFloat3 `xml:"max"`
+}
+
+//ConstraintSpring makes a constraint elastic.
+type ConstraintSpring struct {
+	Angular *Spring `xml:"angular"`
+	Linear  *Spring `xml:"linear"`
+}
+
+//Spring describes the elasticity of a constraint.
+type Spring struct {
+	Stiffness   *Float `xml:"stiffness"`
+	Damping     *Float `xml:"damping"`
+	TargetValue *Float `xml:"target_value"`
+}
+
+//InstancePhysicsModel instantiates a physics model, optionally relative to a node given by Parent.
+type InstancePhysicsModel struct {
+	HasSid
+	HasName
+	HasUrl
+	Parent                  Uri                        `xml:"parent,attr,omitempty"`
+	InstanceForceField      []*InstanceForceField      `xml:"instance_force_field"`
+	InstanceRigidBody       []*InstanceRigidBody       `xml:"instance_rigid_body"`
+	InstanceRigidConstraint []*InstanceRigidConstraint `xml:"instance_rigid_constraint"`
+	HasExtra
+}
+
+//InstanceRigidBody instantiates a rigid body of the physics model, moving the node given by Target.
+type InstanceRigidBody struct {
+	Body string `xml:"body,attr"`
+	HasSid
+	HasName
+	Target          Uri                              `xml:"target,attr"`
+	TechniqueCommon InstanceRigidBodyTechniqueCommon `xml:"technique_common"`
+	HasTechnique
+	HasExtra
+}
+
+//InstanceRigidBodyTechniqueCommon overrides the properties of a rigid body and sets its initial velocities.
+type InstanceRigidBodyTechniqueCommon struct {
+	AngularVelocity *Float3 `xml:"angular_velocity"`
+	Velocity        *Float3 `xml:"velocity"`
+	RigidBodyTechniqueCommon
+}
+
+//InstanceRigidConstraint instantiates a rigid constraint of the physics model.
+type InstanceRigidConstraint struct {
+	Constraint string `xml:"constraint,attr"`
+	HasSid
+	HasName
+	HasExtra
+}
+
+//LibraryPhysicsScenes provides a library in which to place <physics_scene> elements.
+type LibraryPhysicsScenes struct {
+	HasId
+	HasName
+	HasAsset
+	PhysicsScene []*PhysicsScene `xml:"physics_scene"`
+	HasExtra
+}
+
+//PhysicsScene embodies the physics models and force fields simulated together.
+type PhysicsScene struct {
+	HasId
+	HasName
+	HasAsset
+	InstanceForceField   []*InstanceForceField       `xml:"instance_force_field"`
+	InstancePhysicsModel []*InstancePhysicsModel     `xml:"instance_physics_model"`
+	TechniqueCommon      PhysicsSceneTechniqueCommon `xml:"technique_common"`
+	HasTechnique
+	HasExtra
+}
+
+//PhysicsSceneTechniqueCommon holds the gravity and integration time step of a physics scene.
+type PhysicsSceneTechniqueCommon struct {
+	Gravity  *SidFloat3 `xml:"gravity"`
+	TimeStep *Float     `xml:"time_step"`
+}
@@ -3,13 +3,18 @@ package collada
 import (
 	"bytes"
 	"encoding/xml"
+	"flag"
+	"fmt"
 	"io"
 	"os"
 	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 )
 
+var benchSceneSize = flag.Int("collada.benchsize", 500<<20, "approximate size in bytes of the synthetic scene used by load benchmarks")
+
 var emptyCollada string = `
 <COLLADA version="1.5.0">
 </COLLADA>
@@ -37,6 +42,243 @@ func TestScrewDocument(t *testing.T) {
 	compareColladaFile("screw.dae", t)
 }
 
+//Exporting a loaded export must reproduce it byte for byte
+func TestStableExport(t *testing.T) {
+	collada, err := LoadDocument("screw.dae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := &bytes.Buffer{}
+	if err := collada.ExportToWriter(first); err != nil {
+		t.Fatal(err)
+	}
+	collada, err = LoadDocumentFromReader(bytes.NewReader(first.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := &bytes.Buffer{}
+	if err := collada.ExportToWriter(second); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Error("export is not stable")
+	}
+}
+
+func TestFloatFormat(t *testing.T) {
+	values := FloatValues{1, -0.9999998, 2.83122e-7, 1e6, 0.5}
+	text, _ := values.MarshalText()
+	if string(text) != "1 -0.9999998 2.83122e-7 1e6 0.5" {
+		t.Error("wrong float text", string(text))
+	}
+
+	collada, err := LoadDocument("cube.dae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, formatted := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := collada.ExportToWriter(plain); err != nil {
+		t.Fatal(err)
+	}
+	if err := collada.ExportToWriterWithOptions(formatted, ExportOptions{FloatFormat: FloatFormat{Fmt: 'f', Prec: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`<znear sid="znear">0.100</znear>`,
+		`<translate sid="location">7.481 -6.508 5.344</translate>`,
+		`count="18">0.000 0.000 -1.000 0.000 0.000 1.000 1.000 -0.000 0.000`,
+		`<p>0 0 1 0 2 0 3 0 4 1 7 1`,
+	} {
+		if !strings.Contains(formatted.String(), expected) {
+			t.Error("formatted export lacks", expected)
+		}
+	}
+	reloaded, err := LoadDocumentFromReader(formatted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.LibraryGeometries[0].Geometry[0].Mesh.Source[0].FloatArray.V) != 24 {
+		t.Error("formatted export does not load")
+	}
+	again := &bytes.Buffer{}
+	if err := collada.ExportToWriterWithOptions(again, ExportOptions{}); err != nil || !bytes.Equal(again.Bytes(), plain.Bytes()) {
+		t.Error("default options change the export")
+	}
+}
+
+//testDocuments returns the documents used by the tests, by name.
+func testDocuments(t *testing.T) map[string]string {
+	documents := map[string]string{
+		"binding": bindingDocument, "common": commonDocument, "images": imagesDocument,
+		"kinematics": kinematicsDocument, "param": paramDocument, "typed param": typedParamDocument,
+		"physics": physicsDocument, "render": renderDocument, "glsl": glslDocument, "gles": glesDocument, "cg": cgDocument,
+	}
+	for _, filename := range []string{"cube.dae", "screw.dae"} {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		documents[filename] = string(data)
+	}
+	return documents
+}
+
+//Only typed floating-point fields are formatted, and the shortest format matches the default export
+func TestFloatFormatFields(t *testing.T) {
+	shortest := ExportOptions{FloatFormat: FloatFormat{Fmt: 'g', Prec: -1, CompactExponent: true}}
+	for name, document := range testDocuments(t) {
+		collada, err := LoadDocumentFromReader(strings.NewReader(document))
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		plain, formatted := &bytes.Buffer{}, &bytes.Buffer{}
+		if err := collada.ExportToWriter(plain); err != nil {
+			t.Fatal(name, err)
+		}
+		if err := collada.ExportToWriterWithOptions(formatted, shortest); err != nil {
+			t.Fatal(name, err)
+		}
+		if !bytes.Equal(plain.Bytes(), formatted.Bytes()) {
+			t.Error(name, "formatted export differs from the default export")
+		}
+	}
+	for document, expected := range map[string][]string{
+		strings.Replace(glesDocument, "<states>", `<states><stencil_mask value="255"/>`, 1): {`<constant value="1.000 1.000 1.000 0.500">`, `<stencil_mask value="255"/>`},
+		kinematicsDocument: {`<prismatic sid="axis0">`, `<axis>1.000 0.000 0.000</axis>`},
+		physicsDocument:    {`<gravity>0.000 -9.800 0.000</gravity>`},
+	} {
+		collada, err := LoadDocumentFromReader(strings.NewReader(document))
+		if err != nil {
+			t.Fatal(err)
+		}
+		formatted := &bytes.Buffer{}
+		if err := collada.ExportToWriterWithOptions(formatted, ExportOptions{FloatFormat: FloatFormat{Fmt: 'f', Prec: 3}}); err != nil {
+			t.Fatal(err)
+		}
+		for _, text := range expected {
+			if !strings.Contains(formatted.String(), text) {
+				t.Error("formatted export lacks", text)
+			}
+		}
+	}
+}
+
+//Arrays converted by the worker pool must match a serial conversion
+func TestConcurrentLoad(t *testing.T) {
+	for name, document := range testDocuments(t) {
+		serial, err := LoadDocumentFromReader(strings.NewReader(document))
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		concurrent, err := LoadDocumentFromReaderWithOptions(strings.NewReader(document), LoadOptions{Workers: 3})
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if !reflect.DeepEqual(serial, concurrent) {
+			t.Error(name, "documents differ")
+		}
+	}
+	for _, filename := range []string{"cube.dae", "screw.dae"} {
+		serial, err := LoadDocument(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		concurrent, err := LoadDocumentWithOptions(filename, LoadOptions{Workers: 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(serial, concurrent) {
+			t.Error(filename, "documents differ")
+		}
+		a := serial.LibraryGeometries[0].Geometry[0].Mesh
+		b := concurrent.LibraryGeometries[0].Geometry[0].Mesh
+		for i, source := range a.Source {
+			if !reflect.DeepEqual(source.FloatArray.F(), b.Source[i].FloatArray.F()) {
+				t.Error(filename, "float array differs", source.Id)
+			}
+		}
+		for i, polylist := range a.Polylist {
+			if !reflect.DeepEqual(polylist.P.I(), b.Polylist[i].P.I()) {
+				t.Error(filename, "index array differs", i)
+			}
+			if !reflect.DeepEqual(polylist.VCount.I(), b.Polylist[i].VCount.I()) {
+				t.Error(filename, "vcount array differs", i)
+			}
+		}
+	}
+}
+
+//Every float and int array, including vcounts and the arrays of parameters, waits for the workers
+func TestDeferredArrays(t *testing.T) {
+	arrays := &arrayDecoder{d: xml.NewDecoder(strings.NewReader(`<COLLADA version="1.5.0">
+<library_geometries><geometry><mesh>
+<source><float_array count="3">1 2 3</float_array></source>
+<polylist count="1"><vcount>3</vcount><p>0 1 2</p></polylist>
+</mesh></geometry></library_geometries>
+<library_effects><effect><newparam sid="tint"><float3>1 0.5 0.25</float3></newparam></effect></library_effects>
+</COLLADA>`))}
+	collada := &Collada{}
+	if err := arrays.decodeDocument(collada); err != nil {
+		t.Fatal(err)
+	}
+	if len(arrays.jobs) != 4 {
+		t.Fatal("wrong number of deferred arrays", len(arrays.jobs))
+	}
+	polylist := collada.LibraryGeometries[0].Geometry[0].Mesh.Polylist[0]
+	if len(polylist.VCount.V) != 0 {
+		t.Error("vcount converted before the workers ran")
+	}
+	if err := arrays.run(2); err != nil {
+		t.Fatal(err)
+	}
+	if polylist.VCount.V[0] != 3 || polylist.P.V[2] != 2 || collada.LibraryEffects[0].Effect[0].Newparam[0].Float3.V[2] != 0.25 {
+		t.Error("wrong converted arrays")
+	}
+}
+
+//syntheticScene generates a document of roughly size bytes containing many independent geometries
+func syntheticScene(size int) []byte {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString(`<COLLADA version="1.5.0"><library_geometries>`)
+	for g := 0; buffer.Len() < size; g++ {
+		fmt.Fprintf(buffer, `<geometry id="geometry-%d"><mesh><source id="geometry-%d-positions"><float_array count="3000">`, g, g)
+		for i := 0; i < 3000; i++ {
+			fmt.Fprintf(buffer, "%g ", float64(i*g%997)*0.001953125-1)
+		}
+		buffer.WriteString(`</float_array></source><triangles count="1000"><p>`)
+		for i := 0; i < 3000; i++ {
+			fmt.Fprintf(buffer, "%d ", i%1000)
+		}
+		buffer.WriteString(`</p></triangles></mesh></geometry>`)
+	}
+	buffer.WriteString(`</library_geometries></COLLADA>`)
+	return buffer.Bytes()
+}
+
+var syntheticSceneData []byte
+
+func benchmarkLoad(b *testing.B, workers int) {
+	if syntheticSceneData == nil {
+		syntheticSceneData = syntheticScene(*benchSceneSize)
+	}
+	b.SetBytes(int64(len(syntheticSceneData)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := LoadDocumentFromReaderWithOptions(bytes.NewReader(syntheticSceneData), LoadOptions{Workers: workers})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoadSerial(b *testing.B) {
+	benchmarkLoad(b, 1)
+}
+
+func BenchmarkLoadConcurrent(b *testing.B) {
+	benchmarkLoad(b, runtime.GOMAXPROCS(0))
+}
+
 func compareColladaFile(filename string, t *testing.T) {
 	file, err := os.Open(filename)
 	if err != nil {
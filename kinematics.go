@@ -0,0 +1,590 @@
+package collada
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+)
+
+//KinematicsAxis is a degree of freedom of a kinematics model.
+type KinematicsAxis struct {
+	Joint    *Joint
+	Axis     *JointAxis
+	Revolute bool
+	//Sid is the reference to the axis within the model, the sid of its joint and that of the axis such as "shoulder/axis0".
+	Sid string
+	//Min and Max bound the values of the axis: the <limits> of its joint, overridden by those of the
+	//<axis_info> of the articulated system the chain was resolved through.
+	Min, Max *float64
+	//Locked axes are held at Value, the value given by <bind_joint_axis> or 0, whatever value they are given.
+	Locked bool
+	Value  float64
+}
+
+//KinematicsChain is a kinematics model prepared for evaluation, with its axes depth first through the links
+//and in document order within each joint.
+type KinematicsChain struct {
+	Model *KinematicsModel
+	Axes  []*KinematicsAxis
+	roots []*kinematicsLink
+}
+
+//kinematicsLink is a link of a chain with the joints moving its children.
+type kinematicsLink struct {
+	link     *Link
+	children []*kinematicsAttachment
+}
+
+//kinematicsAttachment is an <attachment_full> with the indices of the axes of its joint.
+type kinematicsAttachment struct {
+	attachment *AttachmentFull
+	axes       []int
+	link       *kinematicsLink
+}
+
+//KinematicsPose is the result of evaluating a chain.
+type KinematicsPose struct {
+	//Values are the joint values used, clamped to the limits of each axis.
+	Values []float64
+	//Links holds the transform of every link of the chain.
+	Links map[*Link]Mat4
+}
+
+//UnmarshalXML decodes a <prismatic> or <revolute> axis of a joint, skipping elements of other kinds.
+func (axis *JointAxis) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return axis.unmarshalXML(d, start, d.DecodeElement)
+}
+
+//unmarshalXML decodes the children through decode.
+func (axis *JointAxis) unmarshalXML(d *xml.Decoder, start xml.StartElement, decode func(v interface{}, start *xml.StartElement) error) error {
+	type jointAxis JointAxis
+	*axis = JointAxis{}
+	switch kind := JointKind(start.Name.Local); kind {
+	case JointPrismatic, JointRevolute:
+		if err := decode((*jointAxis)(axis), &start); err != nil {
+			return err
+		}
+		axis.Kind = kind
+		return nil
+	}
+	return d.Skip()
+}
+
+//MarshalXML encodes the axis as the element of its kind.
+func (axis JointAxis) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return axis.marshalXML(e, start, e.EncodeElement)
+}
+
+//marshalXML encodes the children through encode.
+func (axis JointAxis) marshalXML(e *xml.Encoder, start xml.StartElement, encode func(v interface{}, start xml.StartElement) error) error {
+	type jointAxis JointAxis
+	if axis.Kind == "" {
+		return nil
+	}
+	return encode(jointAxis(axis), xml.StartElement{Name: xml.Name{Local: string(axis.Kind)}})
+}
+
+//KinematicsChain resolves the joints of a kinematics model.
+func (collada *Collada) KinematicsChain(model *KinematicsModel) (*KinematicsChain, error) {
+	chain := &KinematicsChain{Model: model}
+	joints := make(map[*Joint][]int)
+	var build func(link *Link, path map[*Link]bool) (*kinematicsLink, error)
+	build = func(link *Link, path map[*Link]bool) (*kinematicsLink, error) {
+		if path[link] {
+			return nil, fmt.Errorf("collada: kinematics model %q has a cycle of links", model.Id)
+		}
+		path[link] = true
+		defer delete(path, link)
+		built := &kinematicsLink{link: link}
+		for _, attachment := range link.AttachmentFull {
+			joint, sid, err := collada.modelJoint(model, attachment.Joint)
+			if err != nil {
+				return nil, err
+			}
+			axes, ok := joints[joint]
+			if !ok {
+				for _, axis := range joint.Axis {
+					if axis.Kind != JointPrismatic && axis.Kind != JointRevolute {
+						continue
+					}
+					axes = append(axes, len(chain.Axes))
+					resolved := &KinematicsAxis{Joint: joint, Axis: axis, Revolute: axis.Kind == JointRevolute, Sid: sid + "/" + axis.Sid}
+					if limits := axis.Limits; limits != nil {
+						if limits.Min != nil {
+							min := limits.Min.Value
+							resolved.Min = &min
+						}
+						if limits.Max != nil {
+							max := limits.Max.Value
+							resolved.Max = &max
+						}
+					}
+					chain.Axes = append(chain.Axes, resolved)
+				}
+				joints[joint] = axes
+			}
+			child := &kinematicsAttachment{attachment: attachment, axes: axes}
+			if attachment.Link != nil {
+				if child.link, err = build(attachment.Link, path); err != nil {
+					return nil, err
+				}
+			}
+			built.children = append(built.children, child)
+		}
+		return built, nil
+	}
+	for _, link := range model.TechniqueCommon.Link {
+		root, err := build(link, make(map[*Link]bool))
+		if err != nil {
+			return nil, err
+		}
+		chain.roots = append(chain.roots, root)
+	}
+	return chain, nil
+}
+
+//modelJoint resolves the joint attribute of an attachment, a reference such as "arm/shoulder" whose
+//last component is the sid of a joint or <instance_joint> of the model. It also returns that sid.
+func (collada *Collada) modelJoint(model *KinematicsModel, ref string) (*Joint, string, error) {
+	sid := ref[strings.LastIndex(ref, "/")+1:]
+	for _, joint := range model.TechniqueCommon.Joint {
+		if joint.Sid == sid || string(joint.Id) == sid {
+			return joint, sid, nil
+		}
+	}
+	for _, instance := range model.TechniqueCommon.InstanceJoint {
+		if instance.Sid != sid {
+			continue
+		}
+		if id, ok := instance.Url.Id(); ok {
+			if joint := collada.FindJoint(id); joint != nil {
+				return joint, sid, nil
+			}
+		}
+		return nil, "", fmt.Errorf("collada: instance_joint %q references missing joint %q", sid, instance.Url)
+	}
+	if joint := collada.FindJoint(Id(sid)); joint != nil {
+		return joint, sid, nil
+	}
+	return nil, "", fmt.Errorf("collada: kinematics model %q has no joint %q", model.Id, ref)
+}
+
+//ArticulatedChain resolves the kinematics model of an articulated system, following a <motion> to the system it
+//instantiates. The limits and locks set by the <axis_info> of its <kinematics> override those of the joints.
+func (collada *Collada) ArticulatedChain(system *ArticulatedSystem) (*KinematicsChain, error) {
+	for depth := 0; system.Kinematics == nil; depth++ {
+		if system.Motion == nil || depth > 16 {
+			return nil, fmt.Errorf("collada: articulated system %q has no kinematics", system.Id)
+		}
+		url := system.Motion.InstanceArticulatedSystem.Url
+		id, ok := url.Id()
+		if system = collada.FindArticulatedSystem(id); !ok || system == nil {
+			return nil, fmt.Errorf("collada: missing articulated system %q", url)
+		}
+	}
+	kinematics := system.Kinematics
+	if len(kinematics.InstanceKinematicsModel) == 0 {
+		return nil, fmt.Errorf("collada: articulated system %q has no kinematics model", system.Id)
+	}
+	model, err := collada.instancedKinematicsModel(kinematics.InstanceKinematicsModel[0].Url)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := collada.KinematicsChain(model)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range kinematics.TechniqueCommon.AxisInfo {
+		index, ok := chain.axisIndex(info.Axis)
+		if !ok {
+			return nil, fmt.Errorf("collada: axis_info %q references missing axis %q", info.Sid, info.Axis)
+		}
+		axis := chain.Axes[index]
+		if info.Locked != nil {
+			locked := info.Locked.Bool
+			if info.Locked.Param != "" {
+				if locked = kinematics.param(info, info.Locked.Param).Bool; locked == nil {
+					return nil, fmt.Errorf("collada: axis_info %q has no bool parameter %q", info.Sid, info.Locked.Param)
+				}
+			}
+			axis.Locked = locked != nil && *locked
+		}
+		if info.Limits == nil {
+			continue
+		}
+		for _, limit := range []struct {
+			value  *CommonFloatOrParam
+			target **float64
+		}{{info.Limits.Min, &axis.Min}, {info.Limits.Max, &axis.Max}} {
+			if limit.value == nil {
+				continue
+			}
+			value := limit.value.Float
+			if value == nil && limit.value.Param == "" {
+				continue
+			}
+			if limit.value.Param != "" {
+				if value = kinematics.param(info, limit.value.Param).Float; value == nil {
+					return nil, fmt.Errorf("collada: axis_info %q has no float parameter %q", info.Sid, limit.value.Param)
+				}
+			}
+			v := *value
+			*limit.target = &v
+		}
+	}
+	return chain, nil
+}
+
+//param returns the value of the parameter with the given sid declared by an <axis_info> or by the
+//<instance_kinematics_model> of the kinematics, or an empty value if there is none.
+func (kinematics *Kinematics) param(info *KinematicsAxisInfo, sid string) *ParamValue {
+	newparams := append([]*Newparam{}, info.Newparam...)
+	for _, instance := range kinematics.InstanceKinematicsModel {
+		newparams = append(newparams, instance.Newparam...)
+	}
+	for _, newparam := range newparams {
+		if newparam.Sid == sid {
+			return &newparam.ParamValue
+		}
+	}
+	return &ParamValue{}
+}
+
+//Clamp limits a value of the axis to its Min and Max, or returns the value of a locked axis.
+func (axis *KinematicsAxis) Clamp(value float64) float64 {
+	if axis.Locked {
+		return axis.Value
+	}
+	if axis.Min != nil {
+		value = math.Max(value, *axis.Min)
+	}
+	if axis.Max != nil {
+		value = math.Min(value, *axis.Max)
+	}
+	return value
+}
+
+//Mat4 returns the motion of the axis for a value, in degrees for revolute axes and in the units of the document for prismatic ones.
+func (axis *KinematicsAxis) Mat4(value float64) Mat4 {
+	v := axis.Axis.Axis.F()
+	x, y, z := floatsAt(v, 0), floatsAt(v, 1), floatsAt(v, 2)
+	if axis.Revolute {
+		return Rotation(x, y, z, value)
+	}
+	x, y, z = normalize(x, y, z)
+	return Translation(x*value, y*value, z*value)
+}
+
+//Evaluate computes the transform of every link of the chain, given one value for each of its Axes, relative to base,
+//the transform of the node the model is bound to. Values outside the limits of their axes are clamped.
+func (chain *KinematicsChain) Evaluate(base Mat4, values []float64) (*KinematicsPose, error) {
+	if len(values) != len(chain.Axes) {
+		return nil, fmt.Errorf("collada: kinematics model %q has %d axes, not %d", chain.Model.Id, len(chain.Axes), len(values))
+	}
+	pose := &KinematicsPose{Values: make([]float64, len(values)), Links: make(map[*Link]Mat4)}
+	for i, axis := range chain.Axes {
+		pose.Values[i] = axis.Clamp(values[i])
+	}
+	var walk func(link *kinematicsLink, parent Mat4)
+	walk = func(link *kinematicsLink, parent Mat4) {
+		world := parent.Mul(link.link.Transform.Mat4())
+		pose.Links[link.link] = world
+		for _, child := range link.children {
+			if child.link == nil {
+				continue
+			}
+			m := world.Mul(child.attachment.Transform.Mat4())
+			for _, i := range child.axes {
+				m = m.Mul(chain.Axes[i].Mat4(pose.Values[i]))
+			}
+			walk(child.link, m)
+		}
+	}
+	for _, root := range chain.roots {
+		walk(root, base)
+	}
+	return pose, nil
+}
+
+//FindLink returns the link of the chain with the given sid, or nil if there is none.
+func (chain *KinematicsChain) FindLink(sid string) *Link {
+	var find func(links []*kinematicsLink) *Link
+	find = func(links []*kinematicsLink) *Link {
+		for _, link := range links {
+			if link.link.Sid == sid {
+				return link.link
+			}
+			for _, child := range link.children {
+				if child.link != nil {
+					if found := find([]*kinematicsLink{child.link}); found != nil {
+						return found
+					}
+				}
+			}
+		}
+		return nil
+	}
+	return find(chain.roots)
+}
+
+//axisIndex returns the index of the axis of the chain whose reference ends with the given joint and axis sids.
+func (chain *KinematicsChain) axisIndex(ref string) (int, bool) {
+	parts := strings.Split(ref, "/")
+	if len(parts) < 2 {
+		return 0, false
+	}
+	sid := strings.Join(parts[len(parts)-2:], "/")
+	for i, axis := range chain.Axes {
+		if axis.Sid == sid {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+//KinematicsBinding is a kinematics model bound to the visual scene by an <instance_kinematics_scene>.
+type KinematicsBinding struct {
+	Chain *KinematicsChain
+	//Node is the node bound by <bind_kinematics_model>, and World its transform in the visual scene.
+	Node  *Node
+	World Mat4
+	//Axes lists the node transforms bound to axes of the chain by <bind_joint_axis>.
+	Axes []*BoundAxis
+}
+
+//BoundAxis is an axis of a chain bound to a transform of a node, exactly one of Translate and Rotate.
+type BoundAxis struct {
+	//Index is the index of the axis in the Axes of the chain.
+	Index     int
+	Node      *Node
+	Translate *Translate
+	Rotate    *Rotate
+	//Value is the value given by <bind_joint_axis>, or 0 if it gives none.
+	Value float64
+}
+
+//KinematicsBindings resolves the kinematics models and joint axes an <instance_kinematics_scene> binds to nodes.
+func (collada *Collada) KinematicsBindings(instance *InstanceKinematicsScene) ([]*KinematicsBinding, error) {
+	id, ok := instance.Url.Id()
+	if !ok {
+		return nil, fmt.Errorf("collada: instance_kinematics_scene references external scene %q", instance.Url)
+	}
+	scene := collada.FindKinematicsScene(id)
+	if scene == nil {
+		return nil, fmt.Errorf("collada: instance_kinematics_scene references missing scene %q", id)
+	}
+	bindings := []*KinematicsBinding{}
+	for _, bind := range instance.BindKinematicsModel {
+		ref, err := instance.sidRef(bind.CommonSidrefOrParam)
+		if err != nil {
+			return nil, err
+		}
+		chain, err := collada.sceneKinematicsChain(scene, ref)
+		if err != nil {
+			return nil, err
+		}
+		node, world, ok := collada.nodeRef(bind.Node)
+		if !ok {
+			return nil, fmt.Errorf("collada: kinematics model bound to missing node %q", bind.Node)
+		}
+		bindings = append(bindings, &KinematicsBinding{Chain: chain, Node: node, World: world})
+	}
+	for _, bind := range instance.BindJointAxis {
+		ref, err := instance.sidRef(bind.Axis)
+		if err != nil {
+			return nil, err
+		}
+		binding, index, ok := collada.boundAxis(bindings, ref)
+		if !ok {
+			return nil, fmt.Errorf("collada: cannot resolve joint axis %q", ref)
+		}
+		bound, err := collada.axisTarget(bind.Target)
+		if err != nil {
+			return nil, err
+		}
+		bound.Index = index
+		switch {
+		case bind.Value.Float != nil:
+			bound.Value = *bind.Value.Float
+		case bind.Value.Param != "":
+			for _, newparam := range instance.Newparam {
+				if newparam.Sid == bind.Value.Param && newparam.Float != nil {
+					bound.Value = *newparam.Float
+				}
+			}
+		}
+		if axis := binding.Chain.Axes[index]; axis.Locked {
+			axis.Value = bound.Value
+		}
+		binding.Axes = append(binding.Axes, bound)
+	}
+	return bindings, nil
+}
+
+//sidRef returns a sid reference given directly or by a parameter of the instance.
+func (instance *InstanceKinematicsScene) sidRef(ref CommonSidrefOrParam) (string, error) {
+	if ref.Param == "" {
+		return ref.SidRef, nil
+	}
+	for _, newparam := range instance.Newparam {
+		if newparam.Sid == ref.Param && newparam.SidRef != nil {
+			return *newparam.SidRef, nil
+		}
+	}
+	return "", fmt.Errorf("collada: instance_kinematics_scene has no SIDREF parameter %q", ref.Param)
+}
+
+//sceneKinematicsChain resolves a reference to a model or articulated system instantiated by a kinematics scene
+//to the chain of the kinematics model it is built on.
+func (collada *Collada) sceneKinematicsChain(scene *KinematicsScene, ref string) (*KinematicsChain, error) {
+	sid := ref[strings.LastIndex(ref, "/")+1:]
+	for _, instance := range scene.InstanceKinematicsModel {
+		if instance.Sid == sid {
+			model, err := collada.instancedKinematicsModel(instance.Url)
+			if err != nil {
+				return nil, err
+			}
+			return collada.KinematicsChain(model)
+		}
+	}
+	for _, instance := range scene.InstanceArticulatedSystem {
+		if instance.Sid != sid {
+			continue
+		}
+		id, ok := instance.Url.Id()
+		system := collada.FindArticulatedSystem(id)
+		if !ok || system == nil {
+			return nil, fmt.Errorf("collada: missing articulated system %q", instance.Url)
+		}
+		return collada.ArticulatedChain(system)
+	}
+	return nil, fmt.Errorf("collada: kinematics scene %q has no model %q", scene.Id, ref)
+}
+
+func (collada *Collada) instancedKinematicsModel(url Uri) (*KinematicsModel, error) {
+	id, ok := url.Id()
+	if ok {
+		if model := collada.FindKinematicsModel(id); model != nil {
+			return model, nil
+		}
+	}
+	return nil, fmt.Errorf("collada: missing kinematics model %q", url)
+}
+
+//boundAxis finds the axis of a bound chain named by a reference to the axis of a joint,
+//or to an <axis_info> of an articulated system describing one.
+func (collada *Collada) boundAxis(bindings []*KinematicsBinding, ref string) (*KinematicsBinding, int, bool) {
+	for depth := 0; depth < 16; depth++ {
+		for _, binding := range bindings {
+			if index, ok := binding.Chain.axisIndex(ref); ok {
+				return binding, index, true
+			}
+		}
+		info := collada.axisInfo(ref[strings.LastIndex(ref, "/")+1:])
+		if info == "" {
+			break
+		}
+		ref = info
+	}
+	return nil, 0, false
+}
+
+//axisInfo returns the axis described by the <axis_info> of any articulated system with the given sid.
+func (collada *Collada) axisInfo(sid string) string {
+	for _, library := range collada.LibraryArticulatedSystems {
+		for _, system := range library.ArticulatedSystem {
+			if system.Kinematics != nil {
+				for _, info := range system.Kinematics.TechniqueCommon.AxisInfo {
+					if info.Sid == sid {
+						return info.Axis
+					}
+				}
+			}
+			if system.Motion != nil {
+				for _, info := range system.Motion.TechniqueCommon.AxisInfo {
+					if info.Sid == sid {
+						return info.Axis
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+//nodeRef finds a node of a visual scene, and its transform, by a reference whose last component is its id or sid.
+func (collada *Collada) nodeRef(ref string) (*Node, Mat4, bool) {
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	var found *Node
+	var world Mat4
+	for _, library := range collada.LibraryVisualScenes {
+		for _, scene := range library.VisualScene {
+			collada.VisitNodes(scene, func(node *Node, m Mat4) {
+				if found == nil && (string(node.Id) == name || node.Sid == name) {
+					found, world = node, m
+				}
+			})
+		}
+	}
+	return found, world, found != nil
+}
+
+//axisTarget resolves the target of a <bind_joint_axis>, the sid of a translate or rotate of a node such as "arm/elbow/rotateZ".
+func (collada *Collada) axisTarget(target string) (*BoundAxis, error) {
+	i := strings.LastIndex(target, "/")
+	if i < 0 {
+		return nil, fmt.Errorf("collada: joint axis bound to invalid target %q", target)
+	}
+	node, _, ok := collada.nodeRef(target[:i])
+	if !ok {
+		return nil, fmt.Errorf("collada: joint axis bound to missing node %q", target[:i])
+	}
+	sid := target[i+1:]
+	for _, rotate := range node.Rotate {
+		if rotate.Sid == sid {
+			return &BoundAxis{Node: node, Rotate: rotate}, nil
+		}
+	}
+	for _, translate := range node.Translate {
+		if translate.Sid == sid {
+			return &BoundAxis{Node: node, Translate: translate}, nil
+		}
+	}
+	return nil, fmt.Errorf("collada: joint axis bound to missing transform %q", target)
+}
+
+//Values returns the values given by <bind_joint_axis> for the axes of the chain, with unbound axes 0.
+func (binding *KinematicsBinding) Values() []float64 {
+	values := make([]float64, len(binding.Chain.Axes))
+	for _, bound := range binding.Axes {
+		values[bound.Index] = bound.Value
+	}
+	return values
+}
+
+//Evaluate computes the transforms of the links of the bound chain in the visual scene.
+func (binding *KinematicsBinding) Evaluate(values []float64) (*KinematicsPose, error) {
+	return binding.Chain.Evaluate(binding.World, values)
+}
+
+//Apply sets the node transforms bound to axes of the chain to the given joint values, clamped to the limits of the axes.
+//Rotations take the value as their angle, while translations move along the axis of the joint by the value.
+func (binding *KinematicsBinding) Apply(values []float64) error {
+	if len(values) != len(binding.Chain.Axes) {
+		return fmt.Errorf("collada: kinematics model %q has %d axes, not %d", binding.Chain.Model.Id, len(binding.Chain.Axes), len(values))
+	}
+	for _, bound := range binding.Axes {
+		axis := binding.Chain.Axes[bound.Index]
+		value := axis.Clamp(values[bound.Index])
+		if bound.Rotate != nil {
+			v := bound.Rotate.F()
+			bound.Rotate.V = FloatValues{floatsAt(v, 0), floatsAt(v, 1), floatsAt(v, 2), value}
+			continue
+		}
+		v := axis.Axis.Axis.F()
+		x, y, z := normalize(floatsAt(v, 0), floatsAt(v, 1), floatsAt(v, 2))
+		bound.Translate.V = FloatValues{x * value, y * value, z * value}
+	}
+	return nil
+}
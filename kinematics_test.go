@@ -0,0 +1,255 @@
+package collada
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+const kinematicsDocument = `<?xml version="1.0" encoding="utf-8"?>
+<COLLADA xmlns="http://www.collada.org/2008/03/COLLADASchema" version="1.5.0">
+  <library_joints>
+    <joint id="shoulder" sid="shoulder">
+      <revolute sid="axis0">
+        <axis>0 0 1</axis>
+        <limits><min>-90</min><max>90</max></limits>
+      </revolute>
+    </joint>
+    <joint id="slide" sid="slide">
+      <prismatic sid="axis0"><axis>1 0 0</axis></prismatic>
+    </joint>
+    <joint id="wrist" sid="wrist">
+      <revolute sid="axis0"><axis>1 0 0</axis></revolute>
+      <prismatic sid="axis1"><axis>1 0 0</axis></prismatic>
+    </joint>
+  </library_joints>
+  <library_kinematics_models>
+    <kinematics_model id="arm">
+      <technique_common>
+        <instance_joint sid="shoulder" url="#shoulder"/>
+        <instance_joint sid="slide" url="#slide"/>
+        <link sid="base">
+          <attachment_full joint="arm/shoulder">
+            <translate>0 0 1</translate>
+            <link sid="upper">
+              <attachment_full joint="arm/slide">
+                <translate>1 0 0</translate>
+                <link sid="tool"/>
+              </attachment_full>
+            </link>
+          </attachment_full>
+        </link>
+      </technique_common>
+    </kinematics_model>
+    <kinematics_model id="placed">
+      <technique_common>
+        <instance_joint sid="shoulder" url="#shoulder"/>
+        <link sid="base">
+          <rotate>0 0 1 90</rotate>
+          <translate>1 0 0</translate>
+          <rotate sid="spin">1 0 0 45</rotate>
+          <attachment_full joint="placed/shoulder">
+            <rotate>0 0 1 90</rotate>
+            <translate>1 0 0</translate>
+            <link sid="arm"/>
+          </attachment_full>
+        </link>
+      </technique_common>
+    </kinematics_model>
+  </library_kinematics_models>
+  <library_articulated_systems>
+    <articulated_system id="arm-kinematics">
+      <kinematics>
+        <instance_kinematics_model url="#arm" sid="arm-instance">
+          <newparam sid="arm-instance"><SIDREF>arm-kinematics/arm-instance</SIDREF></newparam>
+        </instance_kinematics_model>
+        <technique_common>
+          <axis_info sid="shoulder-info" axis="arm/shoulder/axis0">
+            <newparam sid="shoulder-max"><float>60</float></newparam>
+            <active><bool>true</bool></active>
+            <locked><bool>false</bool></locked>
+            <index semantic="ROBOT"><int>0</int></index>
+            <limits><min><float>-45</float></min><max><param>shoulder-max</param></max></limits>
+          </axis_info>
+          <axis_info sid="slide-info" axis="arm/slide/axis0">
+            <locked><bool>true</bool></locked>
+          </axis_info>
+          <frame_origin link="arm/base"/>
+          <frame_tip link="arm/tool"><translate>0 0 0.1</translate></frame_tip>
+        </technique_common>
+      </kinematics>
+    </articulated_system>
+    <articulated_system id="arm-motion">
+      <motion>
+        <instance_articulated_system url="#arm-kinematics">
+          <bind symbol="arm-instance"><param ref="arm-kinematics/arm-instance"/></bind>
+        </instance_articulated_system>
+        <technique_common>
+          <axis_info axis="arm-kinematics/shoulder-info"><speed><float>30</float></speed></axis_info>
+          <effector_info><speed><float2>1 30</float2></speed></effector_info>
+        </technique_common>
+      </motion>
+    </articulated_system>
+  </library_articulated_systems>
+  <library_kinematics_scenes>
+    <kinematics_scene id="robot">
+      <instance_articulated_system sid="motion" url="#arm-motion"/>
+    </kinematics_scene>
+  </library_kinematics_scenes>
+  <library_visual_scenes>
+    <visual_scene id="visual">
+      <node id="base" sid="base">
+        <translate>2 0 0</translate>
+        <node id="upper" sid="upper">
+          <translate>0 0 1</translate>
+          <rotate sid="rotate">0 0 1 0</rotate>
+        </node>
+      </node>
+    </visual_scene>
+  </library_visual_scenes>
+  <scene>
+    <instance_kinematics_scene url="#robot">
+      <bind_kinematics_model node="visual/base"><SIDREF>robot/motion</SIDREF></bind_kinematics_model>
+      <bind_joint_axis target="visual/upper/rotate">
+        <axis><SIDREF>robot/motion/shoulder-info</SIDREF></axis>
+        <value><float>30</float></value>
+      </bind_joint_axis>
+    </instance_kinematics_scene>
+  </scene>
+</COLLADA>`
+
+func TestKinematics(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(kinematicsDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(collada *Collada) {
+		shoulder := collada.FindJoint("shoulder")
+		if shoulder == nil || len(shoulder.Axis) != 1 || shoulder.Axis[0].Kind != JointRevolute || shoulder.Axis[0].Limits.Max.Value != 90 || shoulder.Axis[0].Axis.F()[2] != 1 {
+			t.Fatal("wrong revolute joint")
+		}
+		if slide := collada.FindJoint("slide"); len(slide.Axis) != 1 || slide.Axis[0].Kind != JointPrismatic || slide.Axis[0].Limits != nil {
+			t.Error("wrong prismatic joint")
+		}
+		if wrist := collada.FindJoint("wrist").Axis; len(wrist) != 2 || wrist[0].Kind != JointRevolute || wrist[1].Kind != JointPrismatic {
+			t.Error("wrong order of joint axes")
+		}
+		model := collada.FindKinematicsModel("arm").TechniqueCommon
+		upper := model.Link[0].AttachmentFull[0].Link
+		if len(model.InstanceJoint) != 2 || upper.Sid != "upper" || upper.AttachmentFull[0].Link.Sid != "tool" {
+			t.Fatal("wrong links")
+		}
+		placed := collada.FindKinematicsModel("placed").TechniqueCommon.Link[0].Transform
+		if len(placed) != 3 || placed[0].Rotate == nil || placed[1].Translate == nil || placed[2].Rotate.Sid != "spin" {
+			t.Error("wrong order of link transforms")
+		}
+		kinematics := collada.FindArticulatedSystem("arm-kinematics").Kinematics
+		info := kinematics.TechniqueCommon.AxisInfo[0]
+		if !*info.Active.Bool || *info.Index[0].Int != 0 || *info.Limits.Min.Float != -45 || info.Limits.Max.Param != "shoulder-max" {
+			t.Error("wrong axis info")
+		}
+		if *kinematics.InstanceKinematicsModel[0].Newparam[0].SidRef != "arm-kinematics/arm-instance" || kinematics.TechniqueCommon.FrameTip.Link != "arm/tool" {
+			t.Error("wrong kinematics")
+		}
+		motion := collada.FindArticulatedSystem("arm-motion").Motion
+		if motion.InstanceArticulatedSystem.Bind[0].Param.Ref != "arm-kinematics/arm-instance" || motion.TechniqueCommon.EffectorInfo.Speed.Float2.F()[1] != 30 {
+			t.Error("wrong motion")
+		}
+		scene := collada.Scene.InstanceKinematicsScene
+		if collada.FindKinematicsScene("robot") == nil || scene.BindKinematicsModel[0].SidRef != "robot/motion" || *scene.BindJointAxis[0].Value.Float != 30 {
+			t.Error("wrong kinematics scene")
+		}
+	}
+	check(collada)
+
+	buf := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadDocumentFromReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(reloaded)
+}
+
+func TestKinematicsChain(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(kinematicsDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := collada.KinematicsChain(collada.FindKinematicsModel("arm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain.Axes) != 2 || chain.Axes[0].Sid != "shoulder/axis0" || !chain.Axes[0].Revolute || chain.Axes[1].Revolute {
+		t.Fatal("wrong axes")
+	}
+	if _, err := chain.Evaluate(Identity(), []float64{0}); err == nil {
+		t.Error("expected error for missing joint values")
+	}
+	pose, err := chain.Evaluate(Identity(), []float64{120, 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pose.Values[0] != 90 || pose.Values[1] != 0.5 {
+		t.Errorf("joint values not clamped: %v", pose.Values)
+	}
+	near := func(m Mat4, x, y, z float64) bool {
+		px, py, pz := m.TransformPoint(0, 0, 0)
+		return math.Abs(px-x) < 1e-9 && math.Abs(py-y) < 1e-9 && math.Abs(pz-z) < 1e-9
+	}
+	if !near(pose.Links[chain.FindLink("upper")], 0, 0, 1) || !near(pose.Links[chain.FindLink("tool")], 0, 1.5, 1) {
+		t.Error("wrong link transforms")
+	}
+
+	placed, err := collada.KinematicsChain(collada.FindKinematicsModel("placed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pose, err = placed.Evaluate(Identity(), []float64{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !near(pose.Links[placed.FindLink("base")], 0, 1, 0) || !near(pose.Links[placed.FindLink("arm")], -math.Sqrt(0.5), 1, math.Sqrt(0.5)) {
+		t.Error("link transforms not composed in document order")
+	}
+
+	articulated, err := collada.ArticulatedChain(collada.FindArticulatedSystem("arm-motion"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pose, err = articulated.Evaluate(Identity(), []float64{120, 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pose.Values[0] != 60 || pose.Values[1] != 0 {
+		t.Errorf("axis_info limits and locks not applied: %v", pose.Values)
+	}
+	if articulated.Axes[0].Clamp(-60) != -45 || *chain.Axes[0].Min != -90 {
+		t.Error("wrong axis_info minimum")
+	}
+
+	bindings, err := collada.KinematicsBindings(collada.Scene.InstanceKinematicsScene)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binding := bindings[0]
+	if len(bindings) != 1 || binding.Node.Id != "base" || len(binding.Axes) != 1 || binding.Axes[0].Rotate == nil || binding.Axes[0].Value != 30 {
+		t.Fatal("wrong bindings")
+	}
+	pose, err = binding.Evaluate(binding.Values())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !near(pose.Links[chain.FindLink("base")], 2, 0, 0) || !near(pose.Links[chain.FindLink("upper")], 2, 0, 1) {
+		t.Error("wrong bound link transforms")
+	}
+	if err := binding.Apply([]float64{-100, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if binding.Axes[0].Rotate.F()[3] != -45 {
+		t.Errorf("bound rotation not applied: %v", binding.Axes[0].Rotate.F())
+	}
+}
@@ -0,0 +1,668 @@
+package collada
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+//frame is the up axis and unit in effect for an element, inherited from the assets of its ancestors.
+type frame struct {
+	up    UpAxis
+	meter float64
+}
+
+//with returns the frame in effect beneath an element with the given asset.
+func (f frame) with(asset *Asset) frame {
+	if asset == nil {
+		return f
+	}
+	if asset.UpAxis != "" {
+		f.up = asset.UpAxis
+	}
+	if asset.Unit != nil && asset.Unit.Meter > 0 {
+		f.meter = asset.Unit.Meter
+	}
+	return f
+}
+
+//axisRole is the document axis and direction playing the role of right, up or in for an up axis,
+//as tabulated by the specification.
+type axisRole struct {
+	axis int
+	sign float64
+}
+
+var upAxisRoles = map[UpAxis][3]axisRole{
+	Xup: {{1, -1}, {0, 1}, {2, 1}},
+	Yup: {{0, 1}, {1, 1}, {2, 1}},
+	Zup: {{0, 1}, {2, 1}, {1, -1}},
+}
+
+//conversion maps coordinates of one frame to another. As both frames are right handed the axes
+//are a rotation by a signed permutation, which converts single components of animated values exactly.
+type conversion struct {
+	//axis and sign give the target component and direction of each source component.
+	axis  [3]int
+	sign  [3]float64
+	scale float64
+}
+
+func newConversion(from, to frame) conversion {
+	c := conversion{scale: from.meter / to.meter}
+	source, target := upAxisRoles[from.up], upAxisRoles[to.up]
+	for role := range source {
+		c.axis[source[role].axis] = target[role].axis
+		c.sign[source[role].axis] = source[role].sign * target[role].sign
+	}
+	return c
+}
+
+//rotates reports whether the conversion changes the direction of any axis.
+func (c conversion) rotates() bool {
+	return c.axis != [3]int{0, 1, 2} || c.sign != [3]float64{1, 1, 1}
+}
+
+//identity reports whether the conversion leaves coordinates unchanged.
+func (c conversion) identity() bool {
+	return !c.rotates() && c.scale == 1
+}
+
+//direction rotates the vector held by the three values starting at v[i].
+func (c conversion) direction(v []float64, i int) {
+	if i+3 > len(v) {
+		return
+	}
+	var out [3]float64
+	for a := 0; a < 3; a++ {
+		out[c.axis[a]] = c.sign[a] * v[i+a]
+	}
+	copy(v[i:], out[:])
+}
+
+//point rotates and scales the point held by the three values starting at v[i].
+func (c conversion) point(v []float64, i int) {
+	c.direction(v, i)
+	for a := i; a < i+3 && a < len(v); a++ {
+		v[a] *= c.scale
+	}
+}
+
+//matrix conjugates a row-major 4x4 matrix so that it transforms converted coordinates.
+func (c conversion) matrix(m []float64) {
+	if len(m) != 16 {
+		return
+	}
+	var out [16]float64
+	copy(out[:], m)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			out[c.axis[i]*4+c.axis[j]] = c.sign[i] * c.sign[j] * m[i*4+j]
+		}
+		out[c.axis[i]*4+3] = c.sign[i] * c.scale * m[i*4+3]
+		out[12+c.axis[i]] = c.sign[i] * m[12+i] / c.scale
+	}
+	copy(m, out[:])
+}
+
+//unitNames names the common units written to the normalized asset.
+var unitNames = map[float64]string{
+	1:      "meter",
+	0.01:   "centimeter",
+	0.001:  "millimeter",
+	1000:   "kilometer",
+	0.0254: "inch",
+	0.3048: "foot",
+}
+
+//normalizer converts a document to a single frame, remembering the conversion applied to each node
+//so that animations of its transforms can be converted to match.
+type normalizer struct {
+	collada *Collada
+	target  frame
+	nodes   map[*Node]conversion
+	sources map[*Source]bool
+}
+
+//Normalize rewrites the document in place so that it is targetUp and measured in units of targetMeter meters.
+//Geometry sources, node transforms, camera clipping planes and magnifications, light attenuation and
+//animations of node transforms are converted from the up axis and unit in effect for each element,
+//including those declared by the assets of libraries, nodes and other nested elements, which are then removed.
+//Cameras and lights keep their orientation through a child node which rotates them into the new axes.
+//Kinematics and physics are left unconverted: joint axes and limits, gravity, shapes and the frames
+//of rigid bodies keep the values they had in the original axes and unit.
+func (collada *Collada) Normalize(targetUp UpAxis, targetMeter float64) error {
+	if _, ok := upAxisRoles[targetUp]; !ok {
+		return fmt.Errorf("collada: unknown up axis %q", targetUp)
+	}
+	if targetMeter <= 0 {
+		return fmt.Errorf("collada: unit of %v meters is not positive", targetMeter)
+	}
+	n := &normalizer{
+		collada: collada,
+		target:  frame{targetUp, targetMeter},
+		nodes:   make(map[*Node]conversion),
+		sources: make(map[*Source]bool),
+	}
+	root := frame{Yup, 1}.with(collada.Asset)
+	var err error
+	for _, library := range collada.LibraryGeometries {
+		libraryFrame := root.with(library.Asset)
+		for _, geometry := range library.Geometry {
+			if geometry.Mesh != nil {
+				n.mesh(geometry.Mesh, n.convert(libraryFrame, geometry.Asset, &err))
+			}
+			if geometry.ConvexMesh != nil {
+				n.mesh(geometry.ConvexMesh.Mesh(), n.convert(libraryFrame, geometry.Asset, &err))
+			}
+		}
+	}
+	for _, library := range collada.LibraryCameras {
+		libraryFrame := root.with(library.Asset)
+		for _, camera := range library.Camera {
+			n.camera(camera, n.convert(libraryFrame, camera.Asset, &err))
+		}
+	}
+	for _, library := range collada.LibraryLights {
+		libraryFrame := root.with(library.Asset)
+		for _, light := range library.Light {
+			n.light(light, n.convert(libraryFrame, light.Asset, &err))
+		}
+	}
+	for _, library := range collada.LibraryNodes {
+		libraryFrame := root.with(library.Asset)
+		for _, node := range library.Node {
+			n.node(node, libraryFrame, &err)
+		}
+	}
+	for _, library := range collada.LibraryVisualScenes {
+		libraryFrame := root.with(library.Asset)
+		for _, scene := range library.VisualScene {
+			for _, node := range scene.Node {
+				n.node(node, libraryFrame.with(scene.Asset), &err)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	for _, library := range collada.LibraryAnimations {
+		for _, animation := range library.Animation {
+			if err := n.animation(animation); err != nil {
+				return err
+			}
+		}
+	}
+	n.clearAssets()
+	if collada.Asset == nil {
+		collada.Asset = &Asset{}
+	}
+	collada.Asset.UpAxis = targetUp
+	collada.Asset.Unit = &Unit{HasName: HasName{unitNames[targetMeter]}, Meter: targetMeter}
+	return nil
+}
+
+//convert returns the conversion from the frame beneath asset to the target, recording unknown up axes in err.
+func (n *normalizer) convert(parent frame, asset *Asset, err *error) conversion {
+	f := parent.with(asset)
+	if _, ok := upAxisRoles[f.up]; !ok {
+		if *err == nil {
+			*err = fmt.Errorf("collada: unknown up axis %q", f.up)
+		}
+		return newConversion(n.target, n.target)
+	}
+	return newConversion(f, n.target)
+}
+
+//pointSemantics and directionSemantics are the vertex inputs converted as positions and as directions.
+var (
+	pointSemantics     = map[string]bool{"POSITION": true}
+	directionSemantics = map[string]bool{"NORMAL": true, "TANGENT": true, "BINORMAL": true, "TEXTANGENT": true, "TEXBINORMAL": true}
+)
+
+//mesh converts the sources of a mesh read as positions or directions, each exactly once.
+func (n *normalizer) mesh(mesh *Mesh, c conversion) {
+	if c.identity() {
+		return
+	}
+	visit := func(semantic string, uri Uri) {
+		source, err := mesh.source(uri)
+		if err != nil || n.sources[source] {
+			return
+		}
+		switch {
+		case pointSemantics[semantic]:
+			n.sources[source] = true
+			n.source(source, c.point)
+		case directionSemantics[semantic]:
+			n.sources[source] = true
+			n.source(source, c.direction)
+		}
+	}
+	for _, input := range mesh.Vertices.Input {
+		visit(input.Semantic, input.Source)
+	}
+	for _, inputs := range mesh.sharedInputs() {
+		for _, input := range inputs {
+			if input.Semantic != "VERTEX" {
+				visit(input.Semantic, input.Source)
+			}
+		}
+	}
+}
+
+//source applies convert to the first three components of every element of a float source.
+func (n *normalizer) source(source *Source, convert func(v []float64, i int)) {
+	if source.FloatArray == nil || source.TechniqueCommon == nil {
+		return
+	}
+	accessor := source.TechniqueCommon.Accessor
+	stride := int(accessor.Stride)
+	if stride < 3 {
+		return
+	}
+	data := source.FloatArray.V
+	for i := 0; i < accessor.Count; i++ {
+		base := int(accessor.Offset) + i*stride
+		if base+3 > len(data) {
+			break
+		}
+		convert(data, base)
+	}
+}
+
+//camera scales the clipping planes and magnifications of a camera.
+func (n *normalizer) camera(camera *Camera, c conversion) {
+	optics := camera.Optics.TechniqueCommon
+	if p := optics.Perspective; p != nil {
+		p.Znear.Value *= c.scale
+		p.Zfar.Value *= c.scale
+	}
+	if o := optics.Orthographic; o != nil {
+		for _, f := range []*Float{o.Xmag, o.Ymag, &o.Znear, &o.Zfar} {
+			if f != nil {
+				f.Value *= c.scale
+			}
+		}
+	}
+}
+
+//light scales the distance attenuation of point and spot lights.
+func (n *normalizer) light(light *Light, c conversion) {
+	attenuate := func(linear, quadratic *Float) {
+		if linear != nil {
+			linear.Value /= c.scale
+		}
+		if quadratic != nil {
+			quadratic.Value /= c.scale * c.scale
+		}
+	}
+	if point := light.TechniqueCommon.Point; point != nil {
+		attenuate(point.LinearAttenuation, point.QuadraticAttenuation)
+	}
+	if spot := light.TechniqueCommon.Spot; spot != nil {
+		attenuate(spot.LinearAttenuation, spot.QuadraticAttenuation)
+	}
+}
+
+//node converts the transforms of a node and its children, each exactly once.
+func (n *normalizer) node(node *Node, parent frame, err *error) {
+	if _, ok := n.nodes[node]; ok {
+		return
+	}
+	c := n.convert(parent, node.Asset, err)
+	n.nodes[node] = c
+	for _, lookat := range node.Lookat {
+		c.point(lookat.V, 0)
+		c.point(lookat.V, 3)
+		c.direction(lookat.V, 6)
+	}
+	for _, matrix := range node.Matrix {
+		c.matrix(matrix.V)
+	}
+	for _, translate := range node.Translate {
+		c.point(translate.V, 0)
+	}
+	for _, rotate := range node.Rotate {
+		c.direction(rotate.V, 0)
+	}
+	for _, scale := range node.Scale {
+		c.magnitudes(scale.V)
+	}
+	for _, skew := range node.Skew {
+		c.direction(skew.V, 1)
+		c.direction(skew.V, 4)
+	}
+	//Cameras and lights look down their negative z axis, which would otherwise turn with the axes.
+	if c.rotates() && (len(node.InstanceCamera) > 0 || len(node.InstanceLight) > 0) {
+		m := Identity()
+		for a := 0; a < 3; a++ {
+			for b := 0; b < 3; b++ {
+				m[a*4+b] = 0
+			}
+		}
+		for a := 0; a < 3; a++ {
+			m[c.axis[a]*4+a] = c.sign[a]
+		}
+		orientation := &Node{HasName: HasName{"orientation"}, HasType: HasType{"NODE"}}
+		if node.Id != "" {
+			orientation.Id = node.Id + "-orientation"
+		}
+		orientation.Matrix = []*Matrix{NewMatrix(m)}
+		orientation.InstanceCamera, node.InstanceCamera = node.InstanceCamera, nil
+		orientation.InstanceLight, node.InstanceLight = node.InstanceLight, nil
+		n.nodes[orientation] = newConversion(n.target, n.target)
+		node.Node = append(node.Node, orientation)
+	}
+	f := parent.with(node.Asset)
+	for _, child := range node.Node {
+		n.node(child, f, err)
+	}
+}
+
+//magnitudes permutes the components of a scale without changing their sign.
+func (c conversion) magnitudes(v []float64) {
+	if len(v) < 3 {
+		return
+	}
+	var out [3]float64
+	for a := 0; a < 3; a++ {
+		out[c.axis[a]] = v[a]
+	}
+	copy(v, out[:])
+}
+
+//channelTarget splits a target address into the node id, the transform sid and the selected member.
+var channelTarget = regexp.MustCompile(`^([^/]+)/([^/.(]+)(?:\.(\w+)|((?:\(\d+\))+))?$`)
+
+//animation converts the outputs of channels which animate node transforms.
+//Channels of other elements, and members which cannot be converted independently, are left unchanged.
+func (n *normalizer) animation(animation *Animation) error {
+	for _, channel := range animation.Channel {
+		match := channelTarget.FindStringSubmatch(channel.Target)
+		if match == nil {
+			continue
+		}
+		node := n.collada.FindNode(Id(match[1]))
+		c, ok := n.nodes[node]
+		if !ok || c.identity() {
+			continue
+		}
+		kind := node.transformKind(match[2])
+		if kind == "" {
+			continue
+		}
+		sampler := animation.sampler(channel.Source)
+		if sampler == nil {
+			continue
+		}
+		member, index := match[3], match[4]
+		convert, rename, ok := c.animated(kind, member, index)
+		if !ok {
+			continue
+		}
+		for _, input := range sampler.Input {
+			source := animation.source(input.Source)
+			if source == nil || n.sources[source] {
+				continue
+			}
+			switch input.Semantic {
+			case "OUTPUT":
+				n.sources[source] = true
+				n.values(source, convert)
+			case "IN_TANGENT", "OUT_TANGENT":
+				n.sources[source] = true
+				n.tangents(source, animation.outputStride(sampler), convert)
+			}
+		}
+		if rename != "" {
+			channel.Target = match[1] + "/" + match[2] + rename
+		}
+	}
+	for _, child := range animation.Animation {
+		if err := n.animation(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//outputStride returns the number of components of each output of a sampler, or zero if it has none.
+func (animation *Animation) outputStride(sampler *Sampler) int {
+	for _, input := range sampler.Input {
+		if input.Semantic != "OUTPUT" {
+			continue
+		}
+		if source := animation.source(input.Source); source != nil && source.TechniqueCommon != nil {
+			return sourceStride(source)
+		}
+	}
+	return 0
+}
+
+func sourceStride(source *Source) int {
+	if stride := int(source.TechniqueCommon.Accessor.Stride); stride > 1 {
+		return stride
+	}
+	return 1
+}
+
+//tangents converts the tangents of a sampler whose outputs have dimension components. Bezier tangents
+//hold a (time, value) pair for each component, whose values are converted together, while hermite
+//tangents hold a value for each component. Tangents of any other stride are left unchanged.
+func (n *normalizer) tangents(source *Source, dimension int, convert func(v []float64)) {
+	if dimension == 0 || source.TechniqueCommon == nil {
+		return
+	}
+	switch sourceStride(source) {
+	case 2 * dimension:
+		n.values(source, func(v []float64) {
+			value := make([]float64, dimension)
+			for i := range value {
+				value[i] = v[2*i+1]
+			}
+			convert(value)
+			for i := range value {
+				v[2*i+1] = value[i]
+			}
+		})
+	case dimension:
+		n.values(source, convert)
+	}
+}
+
+//values applies convert to every element of a float source as read by its accessor.
+func (n *normalizer) values(source *Source, convert func(v []float64)) {
+	if source.FloatArray == nil || source.TechniqueCommon == nil {
+		return
+	}
+	accessor := source.TechniqueCommon.Accessor
+	stride := sourceStride(source)
+	data := source.FloatArray.V
+	for i := 0; i < accessor.Count; i++ {
+		base := int(accessor.Offset) + i*stride
+		if base+stride > len(data) {
+			break
+		}
+		convert(data[base : base+stride])
+	}
+}
+
+var (
+	componentMembers = map[string]int{"X": 0, "Y": 1, "Z": 2}
+	memberNames      = []string{"X", "Y", "Z"}
+	matrixIndex      = regexp.MustCompile(`\((\d+)\)`)
+)
+
+//animated returns the conversion of animated values of a transform, and the member the values
+//address after conversion if it changes. Members of lookat and skew transforms are not supported.
+func (c conversion) animated(kind, member, index string) (func(v []float64), string, bool) {
+	component := -1
+	if a, ok := componentMembers[member]; ok {
+		component = a
+	}
+	indices := []int{}
+	for _, m := range matrixIndex.FindAllStringSubmatch(index, -1) {
+		i, _ := strconv.Atoi(m[1])
+		indices = append(indices, i)
+	}
+	if len(indices) == 1 && indices[0] < 3 && kind != "matrix" {
+		component = indices[0]
+	}
+	whole := member == "" && index == ""
+	switch {
+	case whole && kind == "translate":
+		return func(v []float64) { c.point(v, 0) }, "", true
+	case whole && kind == "rotate":
+		return func(v []float64) { c.direction(v, 0) }, "", true
+	case whole && kind == "scale":
+		return c.magnitudes, "", true
+	case whole && kind == "matrix":
+		return c.matrix, "", true
+	case kind == "rotate" && (member == "ANGLE" || len(indices) == 1 && indices[0] == 3):
+		return func(v []float64) {}, "", true
+	case component >= 0 && (kind == "translate" || kind == "rotate" || kind == "scale"):
+		factor := c.sign[component]
+		switch kind {
+		case "translate":
+			factor *= c.scale
+		case "scale":
+			factor = 1
+		}
+		rename := "." + memberNames[c.axis[component]]
+		if member == "" {
+			rename = "(" + strconv.Itoa(c.axis[component]) + ")"
+		}
+		return func(v []float64) {
+			for i := range v {
+				v[i] *= factor
+			}
+		}, rename, true
+	case kind == "matrix" && len(indices) == 2 && indices[0] < 4 && indices[1] < 4:
+		row, column := indices[0], indices[1]
+		factor, newRow, newColumn := 1.0, row, column
+		if row < 3 {
+			factor *= c.sign[row]
+			newRow = c.axis[row]
+		}
+		if column < 3 {
+			factor *= c.sign[column]
+			newColumn = c.axis[column]
+		}
+		switch {
+		case row < 3 && column == 3:
+			factor *= c.scale
+		case row == 3 && column < 3:
+			factor /= c.scale
+		}
+		return func(v []float64) {
+			for i := range v {
+				v[i] *= factor
+			}
+		}, "(" + strconv.Itoa(newRow) + ")(" + strconv.Itoa(newColumn) + ")", true
+	}
+	return nil, "", false
+}
+
+//transformKind returns the element name of the transform of a node with the given sid.
+func (node *Node) transformKind(sid string) string {
+	if node == nil {
+		return ""
+	}
+	for _, t := range node.Lookat {
+		if t.Sid == sid {
+			return "lookat"
+		}
+	}
+	for _, t := range node.Matrix {
+		if t.Sid == sid {
+			return "matrix"
+		}
+	}
+	for _, t := range node.Translate {
+		if t.Sid == sid {
+			return "translate"
+		}
+	}
+	for _, t := range node.Rotate {
+		if t.Sid == sid {
+			return "rotate"
+		}
+	}
+	for _, t := range node.Scale {
+		if t.Sid == sid {
+			return "scale"
+		}
+	}
+	for _, t := range node.Skew {
+		if t.Sid == sid {
+			return "skew"
+		}
+	}
+	return ""
+}
+
+//sampler returns the sampler of an animation with the given reference.
+func (animation *Animation) sampler(uri Uri) *Sampler {
+	if id, ok := uri.Id(); ok {
+		for _, sampler := range animation.Sampler {
+			if sampler.Id == id {
+				return sampler
+			}
+		}
+	}
+	return nil
+}
+
+//source returns the source of an animation with the given reference.
+func (animation *Animation) source(uri Uri) *Source {
+	if id, ok := uri.Id(); ok {
+		for _, source := range animation.Source {
+			if source.Id == id {
+				return source
+			}
+		}
+	}
+	return nil
+}
+
+//clearAssets removes the up axis and unit declared beneath the document, which no longer apply.
+func (n *normalizer) clearAssets() {
+	clear := func(asset *Asset) {
+		if asset != nil {
+			asset.UpAxis = ""
+			asset.Unit = nil
+		}
+	}
+	c := n.collada
+	for _, library := range c.LibraryGeometries {
+		clear(library.Asset)
+		for _, geometry := range library.Geometry {
+			clear(geometry.Asset)
+		}
+	}
+	for _, library := range c.LibraryCameras {
+		clear(library.Asset)
+		for _, camera := range library.Camera {
+			clear(camera.Asset)
+		}
+	}
+	for _, library := range c.LibraryLights {
+		clear(library.Asset)
+		for _, light := range library.Light {
+			clear(light.Asset)
+		}
+	}
+	for node := range n.nodes {
+		clear(node.Asset)
+	}
+	for _, library := range c.LibraryNodes {
+		clear(library.Asset)
+	}
+	for _, library := range c.LibraryVisualScenes {
+		clear(library.Asset)
+		for _, scene := range library.VisualScene {
+			clear(scene.Asset)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package collada
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+//worldDirections records the world space direction of the negative z axis of every node instancing a camera or light.
+func worldDirections(collada *Collada) map[string][3]float64 {
+	directions := make(map[string][3]float64)
+	collada.VisitNodes(collada.DefaultVisualScene(), func(node *Node, world Mat4) {
+		for _, instance := range node.InstanceCamera {
+			x, y, z := normalize(world.TransformVector(0, 0, -1))
+			directions[string(instance.Url)] = [3]float64{x, y, z}
+		}
+		for _, instance := range node.InstanceLight {
+			x, y, z := normalize(world.TransformVector(0, 0, -1))
+			directions[string(instance.Url)] = [3]float64{x, y, z}
+		}
+	})
+	return directions
+}
+
+func TestNormalize(t *testing.T) {
+	collada, err := LoadDocument("cube.dae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := collada.bake(&MeshExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	directions := worldDirections(collada)
+	near := collada.LibraryCameras[0].Camera[0].Optics.TechniqueCommon.Perspective.Znear.Value
+	if err := collada.Normalize(Yup, 0.01); err != nil {
+		t.Fatal(err)
+	}
+	after, err := collada.bake(&MeshExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after.vertices) != len(before.vertices) {
+		t.Fatal("wrong vertex count", len(after.vertices))
+	}
+	for i, v := range before.vertices {
+		expected := [3]float64{100 * v.position[0], 100 * v.position[2], -100 * v.position[1]}
+		for a := range expected {
+			if math.Abs(after.vertices[i].position[a]-expected[a]) > 1e-6 {
+				t.Fatal("wrong position", after.vertices[i].position, expected)
+			}
+		}
+		if normal := after.vertices[i].normal; math.Abs(normal[1]-v.normal[2]) > 1e-9 || math.Abs(normal[2]+v.normal[1]) > 1e-9 {
+			t.Fatal("wrong normal", normal, v.normal)
+		}
+	}
+	for url, d := range worldDirections(collada) {
+		expected := directions[url]
+		if math.Abs(d[0]-expected[0]) > 1e-9 || math.Abs(d[1]-expected[2]) > 1e-9 || math.Abs(d[2]+expected[1]) > 1e-9 {
+			t.Error("wrong orientation of", url, d, expected)
+		}
+	}
+	if znear := collada.LibraryCameras[0].Camera[0].Optics.TechniqueCommon.Perspective.Znear.Value; math.Abs(znear-100*near) > 1e-9 {
+		t.Error("wrong near plane", znear)
+	}
+	if collada.Asset.UpAxis != Yup || collada.Asset.Unit.Name != "centimeter" {
+		t.Error("wrong asset", collada.Asset.UpAxis, collada.Asset.Unit)
+	}
+	buffer := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDocumentFromReader(buffer); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNormalizeAnimation(t *testing.T) {
+	doc := NewDocument()
+	doc.Collada.Asset.UpAxis = Zup
+	parent := doc.AddNode("Parent", nil)
+	parent.Asset = &Asset{Unit: &Unit{HasName: HasName{"centimeter"}, Meter: 0.01}}
+	node := doc.AddNode("Child", parent)
+	node.Translate = []*Translate{NewTranslate(1, 2, 3), NewTranslate(0, 0, 0)}
+	node.Translate[0].Sid = "location"
+	node.Translate[1].Sid = "offset"
+	node.Rotate = []*Rotate{NewRotate(0, 0, 1, 45), NewRotate(1, 0, 0, 0)}
+	node.Rotate[0].Sid = "rotationZ"
+	node.Rotate[1].Sid = "tilt"
+	times := doc.newSource("times", []float64{0, 1}, "TIME")
+	values := doc.newSource("values", []float64{2, 4}, "Y")
+	angles := doc.newSource("angles", []float64{45, 90}, "ANGLE")
+	offsets := doc.newSource("offsets", []float64{0, 0, 0, 1, 2, 3}, "X", "Y", "Z")
+	tangents := doc.newSource("tangents", []float64{0.5, 0, 0.5, 0, 0.5, 0, 0.5, 1, 0.5, 2, 0.5, 3}, "X.TIME", "X", "Y.TIME", "Y", "Z.TIME", "Z")
+	tilts := doc.newSource("tilts", []float64{1, 0, 0, 10, 1, 0, 0, 20}, "X", "Y", "Z", "ANGLE")
+	hermite := doc.newSource("hermite", []float64{0, 0, 1, 5, 0, 0, 1, 5}, "X", "Y", "Z", "ANGLE")
+	sampler := func(id string, output *Source) *Sampler {
+		return &Sampler{HasId: HasId{Id(id)}, Input: []*InputUnshared{
+			{Semantic: "INPUT", Source: times.Id.Uri()},
+			{Semantic: "OUTPUT", Source: output.Id.Uri()},
+		}}
+	}
+	animation := &Animation{
+		Source:  []*Source{times, values, angles, offsets, tangents, tilts, hermite},
+		Sampler: []*Sampler{sampler("location-sampler", values), sampler("angle-sampler", angles), sampler("offset-sampler", offsets), sampler("tilt-sampler", tilts)},
+		Channel: []*Channel{
+			{Source: "#location-sampler", Target: "Child/location.Y"},
+			{Source: "#angle-sampler", Target: "Child/rotationZ.ANGLE"},
+			{Source: "#offset-sampler", Target: "Child/offset"},
+			{Source: "#tilt-sampler", Target: "Child/tilt"},
+		},
+	}
+	animation.Sampler[2].Input = append(animation.Sampler[2].Input, &InputUnshared{Semantic: "OUT_TANGENT", Source: tangents.Id.Uri()})
+	animation.Sampler[3].Input = append(animation.Sampler[3].Input, &InputUnshared{Semantic: "IN_TANGENT", Source: hermite.Id.Uri()})
+	doc.Collada.LibraryAnimations = []*LibraryAnimations{{Animation: []*Animation{animation}}}
+	if err := doc.Collada.Normalize(Yup, 1); err != nil {
+		t.Fatal(err)
+	}
+	if translate := node.Translate[0].V; translate[0] != 0.01 || translate[1] != 0.03 || translate[2] != -0.02 {
+		t.Error("wrong translation", translate)
+	}
+	if rotate := node.Rotate[0].V; rotate[1] != 1 || rotate[3] != 45 {
+		t.Error("wrong rotation", rotate)
+	}
+	if target := animation.Channel[0].Target; target != "Child/location.Z" {
+		t.Error("wrong channel target", target)
+	}
+	if v := values.FloatArray.V; v[0] != -0.02 || v[1] != -0.04 {
+		t.Error("wrong animated values", v)
+	}
+	if v := angles.FloatArray.V; v[1] != 90 || animation.Channel[1].Target != "Child/rotationZ.ANGLE" {
+		t.Error("wrong animated angle", v)
+	}
+	if v := offsets.FloatArray.V; v[3] != 0.01 || v[4] != 0.03 || v[5] != -0.02 {
+		t.Error("wrong animated translation", v)
+	}
+	if v := tangents.FloatArray.V; v[6] != 0.5 || v[7] != 0.01 || v[8] != 0.5 || v[9] != 0.03 || v[10] != 0.5 || v[11] != -0.02 {
+		t.Error("wrong animated translation tangents", v)
+	}
+	if v := hermite.FloatArray.V; v[1] != 1 || v[2] != 0 || v[3] != 5 || v[5] != 1 || v[7] != 5 {
+		t.Error("wrong hermite rotation tangents", v)
+	}
+	if parent.Asset.Unit != nil {
+		t.Error("nested unit not removed")
+	}
+}
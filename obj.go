@@ -0,0 +1,819 @@
+package collada
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//objWriter accumulates the OBJ output of a scene, numbering elements across the whole file.
+type objWriter struct {
+	w         *bufio.Writer
+	collada   *Collada
+	vertices  int
+	texcoords int
+	normals   int
+	materials []*Material
+	names     map[*Material]string
+	used      map[string]bool
+}
+
+//ExportObj writes the default visual scene to filename as Wavefront OBJ,
+//with its materials in an MTL file of the same name.
+//Texture maps are resolved against location, the path of the document, as by ImagePath.
+func (collada *Collada) ExportObj(filename, location string) error {
+	mtlFilename := strings.TrimSuffix(filename, filepath.Ext(filename)) + ".mtl"
+	obj, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+	mtl, err := os.Create(mtlFilename)
+	if err != nil {
+		return err
+	}
+	defer mtl.Close()
+	return collada.exportObj(obj, mtl, filepath.Base(mtlFilename), filepath.Dir(mtlFilename), location)
+}
+
+//ExportObjToWriter writes the default visual scene as OBJ to obj and its materials as MTL to mtl.
+//Geometry is baked into world space and mtllib names the material library within the OBJ.
+//Texture maps are resolved against location, the path of the document, and written relative to the working directory.
+func (collada *Collada) ExportObjToWriter(obj, mtl io.Writer, mtllib, location string) error {
+	return collada.exportObj(obj, mtl, mtllib, "", location)
+}
+
+//exportObj writes the OBJ and MTL output with texture maps relative to dir, the directory of the MTL file.
+func (collada *Collada) exportObj(obj, mtl io.Writer, mtllib, dir, location string) error {
+	scene := collada.DefaultVisualScene()
+	if scene == nil {
+		return fmt.Errorf("collada: document has no visual scene")
+	}
+	o := &objWriter{
+		w:       bufio.NewWriter(obj),
+		collada: collada,
+		names:   make(map[*Material]string),
+		used:    make(map[string]bool),
+	}
+	if mtllib != "" {
+		fmt.Fprintf(o.w, "mtllib %s\n", mtllib)
+	}
+	var err error
+	collada.VisitNodes(scene, func(node *Node, world Mat4) {
+		for _, instance := range node.InstanceGeometry {
+			if err == nil {
+				err = o.writeInstance(node, instance, world)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if err = o.w.Flush(); err != nil {
+		return err
+	}
+	return collada.writeMtl(mtl, o.materials, o.names, dir, location)
+}
+
+//BoundMaterials maps the material symbols of an instanced geometry to the materials bound to them.
+func (collada *Collada) BoundMaterials(bind *BindMaterial) map[string]*Material {
+	materials := make(map[string]*Material)
+	if bind == nil {
+		return materials
+	}
+	for _, instance := range bind.TechniqueCommon.InstanceMaterial {
+		if id, ok := instance.Target.Id(); ok {
+			if material := collada.FindMaterial(id); material != nil {
+				materials[instance.Symbol] = material
+			}
+		}
+	}
+	return materials
+}
+
+func objName(name string) string {
+	return strings.Join(strings.Fields(name), "_")
+}
+
+//materialName returns a unique OBJ name for a material, registering it for the MTL output.
+func (o *objWriter) materialName(material *Material) string {
+	if name, ok := o.names[material]; ok {
+		return name
+	}
+	base := objName(material.Name)
+	if base == "" {
+		base = objName(string(material.Id))
+	}
+	name := base
+	for i := 1; o.used[name]; i++ {
+		name = fmt.Sprintf("%s.%d", base, i)
+	}
+	o.used[name] = true
+	o.names[material] = name
+	o.materials = append(o.materials, material)
+	return name
+}
+
+func (o *objWriter) writeInstance(node *Node, instance *InstanceGeometry, world Mat4) error {
+	id, ok := instance.Url.Id()
+	if !ok {
+		return nil
+	}
+	geometry := o.collada.FindGeometry(id)
+	if geometry == nil || geometry.Mesh == nil {
+		return nil
+	}
+	primitives, err := geometry.Mesh.Primitives()
+	if err != nil {
+		return err
+	}
+	name := node.Name
+	if name == "" {
+		name = string(node.Id)
+	}
+	fmt.Fprintf(o.w, "o %s\n", objName(name))
+	materials := o.collada.BoundMaterials(instance.BindMaterial)
+	normalMatrix := world.NormalMatrix()
+	for _, primitive := range primitives {
+		positions := primitive.Attribute("POSITION")
+		if positions == nil {
+			continue
+		}
+		texcoords := primitive.Attribute("TEXCOORD")
+		normals := primitive.Attribute("NORMAL")
+		vs := o.writeElements(positions, "v", func(e []float64) []float64 {
+			x, y, z := world.TransformPoint(floatsAt(e, 0), floatsAt(e, 1), floatsAt(e, 2))
+			return []float64{x, y, z}
+		}, &o.vertices)
+		var vts, vns []int
+		if texcoords != nil {
+			vts = o.writeElements(texcoords, "vt", func(e []float64) []float64 {
+				return []float64{floatsAt(e, 0), floatsAt(e, 1)}
+			}, &o.texcoords)
+		}
+		if normals != nil {
+			vns = o.writeElements(normals, "vn", func(e []float64) []float64 {
+				x, y, z := normalize(normalMatrix.TransformVector(floatsAt(e, 0), floatsAt(e, 1), floatsAt(e, 2)))
+				return []float64{x, y, z}
+			}, &o.normals)
+		}
+		if material, ok := materials[primitive.Material]; ok {
+			fmt.Fprintf(o.w, "usemtl %s\n", o.materialName(material))
+		}
+		corner := 0
+		for _, n := range primitive.VCount {
+			o.w.WriteString("f")
+			for i := 0; i < n; i++ {
+				fmt.Fprintf(o.w, " %d", vs[corner])
+				switch {
+				case vts != nil && vns != nil:
+					fmt.Fprintf(o.w, "/%d/%d", vts[corner], vns[corner])
+				case vts != nil:
+					fmt.Fprintf(o.w, "/%d", vts[corner])
+				case vns != nil:
+					fmt.Fprintf(o.w, "//%d", vns[corner])
+				}
+				corner++
+			}
+			o.w.WriteString("\n")
+		}
+	}
+	return nil
+}
+
+//writeElements writes each element of an attribute used by the primitive once, returning the
+//one-based OBJ index used by every corner.
+func (o *objWriter) writeElements(attribute *Attribute, keyword string, convert func([]float64) []float64, count *int) []int {
+	written := make(map[int]int)
+	indices := make([]int, len(attribute.Indices))
+	for corner, index := range attribute.Indices {
+		if n, ok := written[index]; ok {
+			indices[corner] = n
+			continue
+		}
+		*count++
+		written[index] = *count
+		indices[corner] = *count
+		o.w.WriteString(keyword)
+		for _, f := range convert(attribute.Element(corner)) {
+			o.w.WriteString(" ")
+			o.w.WriteString(formatFloat(f))
+		}
+		o.w.WriteString("\n")
+	}
+	return indices
+}
+
+func (collada *Collada) writeMtl(writer io.Writer, materials []*Material, names map[*Material]string, dir, location string) error {
+	w := bufio.NewWriter(writer)
+	color := func(keyword string, c *CommonColor) {
+		if rgba, ok := c.RGBA(); ok {
+			fmt.Fprintf(w, "%s %s %s %s\n", keyword, formatFloat(rgba[0]), formatFloat(rgba[1]), formatFloat(rgba[2]))
+		}
+	}
+	texture := func(keyword string, common *CommonMaterial, c *CommonColor) {
+		if path, ok := collada.mtlTexture(common, c, dir, location); ok {
+			fmt.Fprintf(w, "%s %s\n", keyword, path)
+		}
+	}
+	scalar := func(keyword string, f *CommonFloat) {
+		if v, ok := f.Float(); ok {
+			fmt.Fprintf(w, "%s %s\n", keyword, formatFloat(v))
+		}
+	}
+	for i, material := range materials {
+		if i > 0 {
+			w.WriteString("\n")
+		}
+		fmt.Fprintf(w, "newmtl %s\n", names[material])
+		common := collada.CommonMaterial(material)
+		if common == nil {
+			continue
+		}
+		color("Ka", common.Ambient)
+		color("Kd", common.Diffuse)
+		color("Ks", common.Specular)
+		color("Ke", common.Emission)
+		texture("map_Ka", common, common.Ambient)
+		texture("map_Kd", common, common.Diffuse)
+		texture("map_Ks", common, common.Specular)
+		texture("map_Ke", common, common.Emission)
+		texture("map_d", common, common.Transparent)
+		scalar("Ns", common.Shininess)
+		scalar("Ni", common.IndexOfRefraction)
+		if d, ok := common.Opacity(); ok {
+			fmt.Fprintf(w, "d %s\n", formatFloat(d))
+		}
+		switch common.Model {
+		case ShadingConstant:
+			w.WriteString("illum 0\n")
+		case ShadingLambert:
+			w.WriteString("illum 1\n")
+		default:
+			w.WriteString("illum 2\n")
+		}
+	}
+	return w.Flush()
+}
+
+//mtlTexture returns the path, relative to dir where possible, of the image file of a textured color
+//in the document at location. Textures which cannot be resolved to a file, such as embedded images, are skipped.
+func (collada *Collada) mtlTexture(common *CommonMaterial, c *CommonColor, dir, location string) (string, bool) {
+	if c == nil || c.Texture == nil {
+		return "", false
+	}
+	_, image, err := common.Scope.Texture(c.Texture)
+	if err != nil {
+		return "", false
+	}
+	path, err := collada.ImagePath(image, location)
+	if err != nil {
+		return "", false
+	}
+	if base, err := filepath.Abs(dir); err == nil {
+		if rel, err := filepath.Rel(base, path); err == nil {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path), true
+}
+
+func formatFloat(f float64) string {
+	return string(FloatFormat{}.append(nil, f))
+}
+
+//objFaceKey identifies the faces or lines of an OBJ group which share a material and vertex layout.
+type objFaceKey struct {
+	material string
+	texcoord bool
+	normal   bool
+	line     bool
+}
+
+//objFaces holds the polygons of one polylist, or the lines of one linestrips, with the position,
+//texcoord and normal index of each corner.
+type objFaces struct {
+	vcount  []int
+	corners [][3]int
+}
+
+type objGroup struct {
+	name  string
+	keys  []objFaceKey
+	faces map[objFaceKey]*objFaces
+}
+
+type objReader struct {
+	positions []float64
+	texcoords []float64
+	normals   []float64
+	groups    []*objGroup
+	current   *objGroup
+	material  string
+	libraries []string
+}
+
+//mtlMaterial is a material read from an MTL library.
+type mtlMaterial struct {
+	name  string
+	phong *Phong
+	maps  []mtlMap
+}
+
+//mtlMap is a texture map of an MTL material, with the uri of its image relative to the OBJ file.
+type mtlMap struct {
+	keyword string
+	uri     Uri
+}
+
+//LoadObj reads a Wavefront OBJ file, along with the MTL libraries it references, into a Collada document.
+func LoadObj(filename string) (*Collada, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	dir := filepath.Dir(filename)
+	return LoadObjFromReader(file, func(name string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+	})
+}
+
+//LoadObjFromReader reads a Wavefront OBJ document into a Collada document with a node for every object or group.
+//Faces become polylists and lines become linestrips; points have no COLLADA primitive and are skipped.
+//Material libraries named by mtllib are read through openMtl, which may be nil to ignore them.
+//Their texture maps become images sampled by the effect of the material.
+func LoadObjFromReader(reader io.Reader, openMtl func(name string) (io.ReadCloser, error)) (*Collada, error) {
+	r := &objReader{}
+	if err := r.read(reader); err != nil {
+		return nil, err
+	}
+	materials := []*mtlMaterial{}
+	if openMtl != nil {
+		for _, library := range r.libraries {
+			mtl, err := openMtl(library)
+			if err != nil {
+				return nil, err
+			}
+			read, err := readMtl(mtl, path.Dir(library))
+			mtl.Close()
+			if err != nil {
+				return nil, err
+			}
+			materials = append(materials, read...)
+		}
+	}
+	return r.build(materials), nil
+}
+
+func parseFloats(fields []string, line int) ([]float64, error) {
+	vs := make([]float64, len(fields))
+	for i, field := range fields {
+		f, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("collada: line %d: %v", line, err)
+		}
+		vs[i] = f
+	}
+	return vs, nil
+}
+
+//objIndex converts a one-based or negative relative OBJ index to a zero-based index.
+func objIndex(field string, count int, line int) (int, error) {
+	n, err := strconv.Atoi(field)
+	if err != nil {
+		return 0, fmt.Errorf("collada: line %d: %v", line, err)
+	}
+	if n < 0 {
+		n += count
+	} else {
+		n--
+	}
+	if n < 0 || n >= count {
+		return 0, fmt.Errorf("collada: line %d: index %s out of range", line, field)
+	}
+	return n, nil
+}
+
+func (r *objReader) group(name string) {
+	r.current = &objGroup{name: name, faces: make(map[objFaceKey]*objFaces)}
+	r.groups = append(r.groups, r.current)
+}
+
+func (r *objReader) read(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		args := fields[1:]
+		switch fields[0] {
+		case "v", "vn":
+			vs, err := parseFloats(args, line)
+			if err != nil {
+				return err
+			}
+			if len(vs) < 3 {
+				return fmt.Errorf("collada: line %d: expected 3 coordinates", line)
+			}
+			if fields[0] == "v" {
+				r.positions = append(r.positions, vs[:3]...)
+			} else {
+				r.normals = append(r.normals, vs[:3]...)
+			}
+		case "vt":
+			vs, err := parseFloats(args, line)
+			if err != nil {
+				return err
+			}
+			vs = append(vs, 0, 0)
+			r.texcoords = append(r.texcoords, vs[:2]...)
+		case "o", "g":
+			r.group(strings.Join(args, " "))
+		case "usemtl":
+			r.material = strings.Join(args, " ")
+		case "mtllib":
+			r.libraries = append(r.libraries, args...)
+		case "f":
+			if err := r.face(args, line, false); err != nil {
+				return err
+			}
+		case "l":
+			if err := r.face(args, line, true); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+//face reads the corners of a face, or of a line if isLine is set.
+func (r *objReader) face(args []string, line int, isLine bool) error {
+	if isLine && len(args) < 2 {
+		return fmt.Errorf("collada: line %d: line has fewer than 2 vertices", line)
+	}
+	if !isLine && len(args) < 3 {
+		return fmt.Errorf("collada: line %d: face has fewer than 3 vertices", line)
+	}
+	if r.current == nil {
+		r.group("default")
+	}
+	corners := make([][3]int, len(args))
+	key := objFaceKey{material: r.material, line: isLine}
+	for i, arg := range args {
+		parts := strings.Split(arg, "/")
+		if i == 0 {
+			key.texcoord = len(parts) > 1 && parts[1] != ""
+			key.normal = len(parts) > 2 && parts[2] != ""
+		}
+		var err error
+		corners[i][0], err = objIndex(parts[0], len(r.positions)/3, line)
+		if err != nil {
+			return err
+		}
+		if key.texcoord {
+			if len(parts) < 2 || parts[1] == "" {
+				return fmt.Errorf("collada: line %d: inconsistent face vertices", line)
+			}
+			if corners[i][1], err = objIndex(parts[1], len(r.texcoords)/2, line); err != nil {
+				return err
+			}
+		}
+		if key.normal {
+			if len(parts) < 3 || parts[2] == "" {
+				return fmt.Errorf("collada: line %d: inconsistent face vertices", line)
+			}
+			if corners[i][2], err = objIndex(parts[2], len(r.normals)/3, line); err != nil {
+				return err
+			}
+		}
+	}
+	faces, ok := r.current.faces[key]
+	if !ok {
+		faces = &objFaces{}
+		r.current.faces[key] = faces
+		r.current.keys = append(r.current.keys, key)
+	}
+	faces.vcount = append(faces.vcount, len(corners))
+	faces.corners = append(faces.corners, corners...)
+	return nil
+}
+
+//setMap sets a texture map of the material, replacing any earlier map of the same kind.
+func (m *mtlMaterial) setMap(texture mtlMap) {
+	for i, existing := range m.maps {
+		if existing.keyword == texture.keyword {
+			m.maps[i] = texture
+			return
+		}
+	}
+	m.maps = append(m.maps, texture)
+}
+
+//readMtl reads the materials of an MTL library, whose texture maps are relative to dir.
+func readMtl(reader io.Reader, dir string) ([]*mtlMaterial, error) {
+	materials := []*mtlMaterial{}
+	var current *mtlMaterial
+	scanner := bufio.NewScanner(reader)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if fields[0] == "newmtl" {
+			current = &mtlMaterial{name: strings.Join(fields[1:], " "), phong: &Phong{}}
+			materials = append(materials, current)
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		switch fields[0] {
+		case "Ka", "Kd", "Ks", "Ke":
+			vs, err := parseFloats(fields[1:], line)
+			if err != nil {
+				return nil, err
+			}
+			if len(vs) < 3 {
+				return nil, fmt.Errorf("collada: line %d: expected 3 color components", line)
+			}
+			color := [4]float64{vs[0], vs[1], vs[2], 1}
+			switch fields[0] {
+			case "Ka":
+				current.phong.AmbientFx = NewColor("ambient", color)
+			case "Kd":
+				current.phong.Diffuse = NewColor("diffuse", color)
+			case "Ks":
+				current.phong.Specular = NewColor("specular", color)
+			case "Ke":
+				current.phong.Emission = NewColor("emission", color)
+			}
+		case "Ns", "Ni", "d", "Tr":
+			vs, err := parseFloats(fields[1:], line)
+			if err != nil {
+				return nil, err
+			}
+			if len(vs) < 1 {
+				return nil, fmt.Errorf("collada: line %d: expected a value", line)
+			}
+			switch fields[0] {
+			case "Ns":
+				current.phong.Shininess = NewFloat("shininess", vs[0])
+			case "Ni":
+				current.phong.IndexOfRefraction = NewFloat("index_of_refraction", vs[0])
+			case "d":
+				current.phong.Transparent = NewColor("transparent", [4]float64{1, 1, 1, 1})
+				current.phong.Transparency = NewFloat("transparency", vs[0])
+			case "Tr":
+				current.phong.Transparent = NewColor("transparent", [4]float64{1, 1, 1, 1})
+				current.phong.Transparency = NewFloat("transparency", 1-vs[0])
+			}
+		case "map_Ka", "map_Kd", "map_Ks", "map_Ke", "map_d":
+			file := mtlMapFile(fields[1:])
+			if file == "" {
+				return nil, fmt.Errorf("collada: line %d: expected a texture map file", line)
+			}
+			current.setMap(mtlMap{fields[0], mtlMapUri(file, dir)})
+		}
+	}
+	return materials, scanner.Err()
+}
+
+//mtlMapOptions holds the number of arguments of each option of a texture map statement.
+//Only the first of the three numbers taken by -o, -s and -t is required.
+var mtlMapOptions = map[string]int{
+	"-blendu": 1, "-blendv": 1, "-bm": 1, "-boost": 1, "-cc": 1, "-clamp": 1,
+	"-imfchan": 1, "-mm": 2, "-texres": 1, "-type": 1, "-o": 3, "-s": 3, "-t": 3,
+}
+
+//mtlMapFile returns the file named by the arguments of a texture map statement, skipping its options.
+func mtlMapFile(args []string) string {
+	for len(args) > 0 {
+		count, ok := mtlMapOptions[args[0]]
+		if !ok {
+			break
+		}
+		args = args[1:]
+		for i := 0; i < count && len(args) > 0; i++ {
+			if _, err := strconv.ParseFloat(args[0], 64); i > 0 && count == 3 && err != nil {
+				break
+			}
+			args = args[1:]
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+//mtlMapUri returns the uri of a texture map file, which is relative to dir unless it is absolute.
+func mtlMapUri(file, dir string) Uri {
+	if windowsPath.MatchString(file) {
+		return Uri(file)
+	}
+	if !path.IsAbs(file) {
+		file = path.Join(dir, file)
+	}
+	return Uri((&url.URL{Path: file}).String())
+}
+
+//mtlTexcoord is the texture coordinate symbol read by the textures of MTL materials.
+const mtlTexcoord = "UVMap"
+
+//addMtlMaps adds the texture maps of an MTL material to its effect, each as an image read through a
+//sampler parameter. Images are shared by the materials which use the same file.
+func (doc *Document) addMtlMaps(effect *Effect, m *mtlMaterial, images map[Uri]*Image) {
+	if len(doc.Collada.LibraryImages) == 0 {
+		doc.Collada.LibraryImages = []*LibraryImages{{}}
+	}
+	library := doc.Collada.LibraryImages[0]
+	for _, texture := range m.maps {
+		image, ok := images[texture.uri]
+		if !ok {
+			file, err := url.PathUnescape(string(texture.uri))
+			if err != nil {
+				file = string(texture.uri)
+			}
+			name := imageFileName(file)
+			name = strings.TrimSuffix(name, path.Ext(name))
+			image = &Image{HasId: HasId{doc.newId(name)}, InitFrom: &InitFrom{Ref: texture.uri}}
+			library.Image = append(library.Image, image)
+			images[texture.uri] = image
+		}
+		sid := strings.TrimPrefix(texture.keyword, "map_") + "-sampler"
+		effect.ProfileCommon.Newparam = append(effect.ProfileCommon.Newparam, &Newparam{
+			HasSid: HasSid{sid},
+			ParamValue: ParamValue{Sampler2D: &Sampler2D{FxSamplerCommon{
+				InstanceImage: &InstanceImage{HasUrl: HasUrl{image.Id.Uri()}},
+			}}},
+		})
+		color := &FxCommonColorOrTextureType{Texture: &Texture{Texture: sid, TexCoord: mtlTexcoord}}
+		switch texture.keyword {
+		case "map_Ka":
+			m.phong.AmbientFx = color
+		case "map_Kd":
+			m.phong.Diffuse = color
+		case "map_Ks":
+			m.phong.Specular = color
+		case "map_Ke":
+			m.phong.Emission = color
+		case "map_d":
+			m.phong.Transparent = color
+		}
+	}
+}
+
+//compact returns the elements of values used by indices and the new index of each.
+func compact(values []float64, size int, indices []int) ([]float64, map[int]int) {
+	remap := make(map[int]int)
+	compacted := []float64{}
+	for _, index := range indices {
+		if _, ok := remap[index]; !ok {
+			remap[index] = len(compacted) / size
+			compacted = append(compacted, values[index*size:(index+1)*size]...)
+		}
+	}
+	return compacted, remap
+}
+
+func (r *objReader) build(mtl []*mtlMaterial) *Collada {
+	doc := NewDocument()
+	materials := make(map[string]*Material)
+	textured := make(map[string]bool)
+	images := make(map[Uri]*Image)
+	for _, m := range mtl {
+		if _, ok := materials[m.name]; !ok {
+			material := doc.AddPhongMaterial(m.name, m.phong)
+			materials[m.name] = material
+			if len(m.maps) > 0 {
+				id, _ := material.InstanceEffect.Url.Id()
+				doc.addMtlMaps(doc.Collada.FindEffect(id), m, images)
+				textured[m.name] = true
+			}
+		}
+	}
+	for _, group := range r.groups {
+		if len(group.keys) == 0 {
+			continue
+		}
+		name := group.name
+		if name == "" {
+			name = "default"
+		}
+		var positions, texcoords, normals []int
+		for _, key := range group.keys {
+			for _, corner := range group.faces[key].corners {
+				positions = append(positions, corner[0])
+				if key.texcoord {
+					texcoords = append(texcoords, corner[1])
+				}
+				if key.normal {
+					normals = append(normals, corner[2])
+				}
+			}
+		}
+		id := doc.newId(name + "-mesh")
+		mesh := &Mesh{}
+		values, positionIndex := compact(r.positions, 3, positions)
+		positionSource := doc.newSource(string(id)+"-positions", values, "X", "Y", "Z")
+		mesh.Source = append(mesh.Source, positionSource)
+		mesh.Vertices = Vertices{
+			HasId: HasId{doc.newId(string(id) + "-vertices")},
+			Input: []*InputUnshared{{Semantic: "POSITION", Source: positionSource.Id.Uri()}},
+		}
+		var texcoordIndex, normalIndex map[int]int
+		var texcoordSource, normalSource *Source
+		if len(texcoords) > 0 {
+			values, texcoordIndex = compact(r.texcoords, 2, texcoords)
+			texcoordSource = doc.newSource(string(id)+"-map", values, "S", "T")
+			mesh.Source = append(mesh.Source, texcoordSource)
+		}
+		if len(normals) > 0 {
+			values, normalIndex = compact(r.normals, 3, normals)
+			normalSource = doc.newSource(string(id)+"-normals", values, "X", "Y", "Z")
+			mesh.Source = append(mesh.Source, normalSource)
+		}
+		geometry := &Geometry{HasId: HasId{id}, HasName: HasName{name}, Mesh: mesh}
+		library := doc.Collada.LibraryGeometries[0]
+		library.Geometry = append(library.Geometry, geometry)
+		node := doc.AddNode(name, nil)
+		instance := &InstanceGeometry{HasUrl: HasUrl{id.Uri()}}
+		node.InstanceGeometry = append(node.InstanceGeometry, instance)
+		bound := make(map[string]bool)
+		for _, key := range group.keys {
+			faces := group.faces[key]
+			inputs := []*InputShared{{Semantic: "VERTEX", Source: mesh.Vertices.Id.Uri()}}
+			if key.texcoord {
+				inputs = append(inputs, &InputShared{Offset: uint(len(inputs)), Semantic: "TEXCOORD", Source: texcoordSource.Id.Uri()})
+			}
+			if key.normal {
+				inputs = append(inputs, &InputShared{Offset: uint(len(inputs)), Semantic: "NORMAL", Source: normalSource.Id.Uri()})
+			}
+			p := make(IntValues, 0, len(faces.corners)*len(inputs))
+			for _, corner := range faces.corners {
+				p = append(p, int32(positionIndex[corner[0]]))
+				if key.texcoord {
+					p = append(p, int32(texcoordIndex[corner[1]]))
+				}
+				if key.normal {
+					p = append(p, int32(normalIndex[corner[2]]))
+				}
+			}
+			var symbol *string
+			if key.line {
+				linestrips := &Linestrips{
+					HasCount:       HasCount{len(faces.vcount)},
+					HasSharedInput: HasSharedInput{inputs},
+				}
+				for _, n := range faces.vcount {
+					n *= len(inputs)
+					linestrips.P = append(linestrips.P, &P{Ints{p[:n:n]}})
+					p = p[n:]
+				}
+				mesh.Linestrips = append(mesh.Linestrips, linestrips)
+				symbol = &linestrips.Material
+			} else {
+				vcount := make(IntValues, len(faces.vcount))
+				for i, n := range faces.vcount {
+					vcount[i] = int32(n)
+				}
+				polylist := &Polylist{
+					HasCount:       HasCount{len(faces.vcount)},
+					HasSharedInput: HasSharedInput{inputs},
+					VCount:         &Ints{vcount},
+					HasP:           HasP{&P{Ints{p}}},
+				}
+				mesh.Polylist = append(mesh.Polylist, polylist)
+				symbol = &polylist.Material
+			}
+			if key.material != "" {
+				material, ok := materials[key.material]
+				if !ok {
+					material = doc.AddMaterial(key.material, [4]float64{0.8, 0.8, 0.8, 1})
+					materials[key.material] = material
+				}
+				*symbol = string(material.Id)
+				if !bound[key.material] {
+					bound[key.material] = true
+					doc.Bind(instance, *symbol, material)
+					if textured[key.material] {
+						bindings := instance.BindMaterial.TechniqueCommon.InstanceMaterial
+						binding := bindings[len(bindings)-1]
+						binding.BindVertexInput = append(binding.BindVertexInput, &BindVertexInput{Semantic: mtlTexcoord, InputSemantic: "TEXCOORD"})
+					}
+				}
+			}
+		}
+	}
+	return doc.Collada
+}
@@ -0,0 +1,246 @@
+package collada
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func countLines(text, prefix string) int {
+	n := 0
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestExportObj(t *testing.T) {
+	collada, err := LoadDocument("cube.dae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := &bytes.Buffer{}
+	mtl := &bytes.Buffer{}
+	if err := collada.ExportObjToWriter(obj, mtl, "cube.mtl", ""); err != nil {
+		t.Fatal(err)
+	}
+	text := obj.String()
+	if !strings.HasPrefix(text, "mtllib cube.mtl\n") {
+		t.Error("missing mtllib")
+	}
+	if n := countLines(text, "v "); n != 8 {
+		t.Error("wrong vertex count", n)
+	}
+	if n := countLines(text, "vn "); n != 6 {
+		t.Error("wrong normal count", n)
+	}
+	if n := countLines(text, "f "); n != 6 {
+		t.Error("wrong face count", n)
+	}
+	if !strings.Contains(text, "usemtl Material\nf 1//1 2//1 3//1 4//1\n") {
+		t.Error("wrong first face", text)
+	}
+	if !strings.Contains(mtl.String(), "newmtl Material\n") || !strings.Contains(mtl.String(), "Kd 0.64 0.64 0.64\n") {
+		t.Error("wrong material library", mtl.String())
+	}
+}
+
+func TestObjWorldTransform(t *testing.T) {
+	doc := NewDocument()
+	geometry, _ := doc.AddGeometry("Point", []float64{1, 0, 0, 0, 1, 0, 0, 0, 1}, nil, nil, []int{0, 1, 2})
+	parent := doc.AddNode("Parent", nil)
+	parent.Translate = append(parent.Translate, NewTranslate(0, 0, 10))
+	child := doc.AddNode("Child", parent)
+	child.Rotate = append(child.Rotate, NewRotate(0, 0, 1, 90))
+	doc.Instance(child, geometry, nil)
+	obj := &bytes.Buffer{}
+	if err := doc.Collada.ExportObjToWriter(obj, &bytes.Buffer{}, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	var x, y, z float64
+	fmt.Sscanf(strings.Split(obj.String(), "\n")[1], "v %g %g %g", &x, &y, &z)
+	if math.Abs(x) > 1e-9 || math.Abs(y-1) > 1e-9 || math.Abs(z-10) > 1e-9 {
+		t.Error("wrong transformed vertex", x, y, z)
+	}
+}
+
+var quadObj = `mtllib quad.mtl
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vt 0 0
+vt 1 0
+vt 1 1
+vt 0 1
+vn 0 0 1
+o Quad
+usemtl Red
+f 1/1/1 2/2/1 3/3/1 -1/-1/-1
+g Triangle
+usemtl Missing
+f 1 2 3
+`
+
+var quadMtl = `newmtl Red
+Kd 1 0 0
+Ns 20
+d 0.5
+`
+
+func TestLoadObj(t *testing.T) {
+	collada, err := LoadObjFromReader(strings.NewReader(quadObj), func(name string) (io.ReadCloser, error) {
+		if name != "quad.mtl" {
+			t.Error("wrong material library", name)
+		}
+		return ioutil.NopCloser(strings.NewReader(quadMtl)), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buffer); err != nil {
+		t.Fatal(err)
+	}
+	collada, err = LoadDocumentFromReader(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	geometries := collada.LibraryGeometries[0].Geometry
+	if len(geometries) != 2 {
+		t.Fatal("wrong geometry count", len(geometries))
+	}
+	quad := geometries[0].Mesh
+	if len(quad.Source) != 3 || quad.Polylist[0].VCount.I()[0] != 4 {
+		t.Error("wrong quad mesh")
+	}
+	primitives, err := quad.Primitives()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uv := primitives[0].Attribute("TEXCOORD").Element(3); uv[0] != 0 || uv[1] != 1 {
+		t.Error("wrong relative texcoord", uv)
+	}
+	materials := collada.LibraryMaterials[0].Material
+	if len(materials) != 2 || materials[0].Name != "Red" || materials[1].Name != "Missing" {
+		t.Fatal("wrong materials", len(materials))
+	}
+	phong := collada.LibraryEffects[0].Effect[0].ProfileCommon.TechniqueFx[0].Phong
+	if phong.Diffuse.Color.F()[0] != 1 || phong.Transparency.Float.Value != 0.5 {
+		t.Error("wrong red effect")
+	}
+	nodes := collada.LibraryVisualScenes[0].VisualScene[0].Node
+	if len(nodes) != 2 || nodes[1].Name != "Triangle" {
+		t.Fatal("wrong nodes")
+	}
+	bound := collada.BoundMaterials(nodes[0].InstanceGeometry[0].BindMaterial)
+	if bound[quad.Polylist[0].Material] != materials[0] {
+		t.Error("quad material is not bound")
+	}
+}
+
+func TestObjTextures(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(paramDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wood, stone := collada.FindMaterial("wood"), collada.FindMaterial("stone")
+	names := map[*Material]string{wood: "wood", stone: "stone"}
+	mtl := &bytes.Buffer{}
+	if err := collada.writeMtl(mtl, []*Material{wood, stone}, names, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(mtl.String(), "newmtl wood\nKa 1 0.5 0.25\nmap_Kd wood.png\n") || !strings.Contains(mtl.String(), "map_Kd stone.png\n") {
+		t.Error("wrong texture maps", mtl.String())
+	}
+	mtl.Reset()
+	if err := collada.writeMtl(mtl, []*Material{wood}, names, "out", ""); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(mtl.String(), "map_Kd ../wood.png\n") {
+		t.Error("texture map not relative to the material library", mtl.String())
+	}
+	mtl.Reset()
+	if err := collada.writeMtl(mtl, []*Material{wood}, names, "out", filepath.Join("models", "scene.dae")); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(mtl.String(), "map_Kd ../models/wood.png\n") {
+		t.Error("texture map not resolved against the document", mtl.String())
+	}
+}
+
+func TestObjRoundTrip(t *testing.T) {
+	collada, err := LoadDocument("screw.dae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := &bytes.Buffer{}
+	mtl := &bytes.Buffer{}
+	if err := collada.ExportObjToWriter(obj, mtl, "screw.mtl", ""); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := LoadObjFromReader(bytes.NewReader(obj.Bytes()), func(string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(mtl.Bytes())), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := collada.LibraryGeometries[0].Geometry[0].Mesh.Polylist[0]
+	polylist := imported.LibraryGeometries[0].Geometry[0].Mesh.Polylist[0]
+	if polylist.Count != original.Count || len(polylist.P.V) != len(original.P.V) {
+		t.Error("wrong polygon count", polylist.Count)
+	}
+}
+
+func TestObjTextureRoundTrip(t *testing.T) {
+	source := `mtllib materials/wood.mtl
+v 0 0 0
+v 1 0 0
+v 1 1 0
+vt 0 0
+vt 1 0
+vt 1 1
+usemtl Wood
+f 1/1 2/2 3/3
+l 1 2 3
+p 1
+`
+	library := `newmtl Wood
+Kd 0.5 0.5 0.5
+map_Kd -s 2 2 maps/wood grain.png
+map_d -clamp on maps/alpha.png
+`
+	collada, err := LoadObjFromReader(strings.NewReader(source), func(name string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader(library)), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	images := collada.LibraryImages[0].Image
+	if len(images) != 2 || images[0].InitFrom.Uri() != "materials/maps/wood%20grain.png" || images[0].Id != "wood_grain" {
+		t.Fatal("wrong images", len(images))
+	}
+	common := collada.CommonMaterial(collada.LibraryMaterials[0].Material[0])
+	if _, image, err := common.Scope.Texture(common.Diffuse.Texture); err != nil || image != images[0] {
+		t.Error("diffuse texture not sampled from its image", err)
+	}
+	mesh := collada.LibraryGeometries[0].Geometry[0].Mesh
+	if len(mesh.Linestrips) != 1 || len(mesh.Linestrips[0].P) != 1 || len(mesh.Linestrips[0].P[0].V) != 3 {
+		t.Error("wrong lines")
+	}
+	obj := &bytes.Buffer{}
+	mtl := &bytes.Buffer{}
+	if err := collada.ExportObjToWriter(obj, mtl, "wood.mtl", ""); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(mtl.String(), "map_Kd materials/maps/wood grain.png\n") || !strings.Contains(mtl.String(), "map_d materials/maps/alpha.png\n") {
+		t.Error("texture maps not written back", mtl.String())
+	}
+}
@@ -0,0 +1,199 @@
+package collada
+
+import (
+	"archive/zip"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//PackagedImage records a file copied by PackageImages.
+type PackagedImage struct {
+	Image *Image
+	//Source is the file which was copied, and Uri the reference which replaced the original.
+	Source string
+	Uri    Uri
+	//Relocated is set if the original reference did not exist and the file was found beside the document instead.
+	Relocated bool
+}
+
+//MissingImage records a reference which PackageImages could not read. The reference is left unchanged,
+//apart from having the xml:base of the document folded into it.
+type MissingImage struct {
+	Image *Image
+	Uri   Uri
+	Err   error
+}
+
+//PackageReport lists the images copied and missing while packaging a document.
+type PackageReport struct {
+	Packaged []*PackagedImage
+	Missing  []*MissingImage
+}
+
+//PackageImages copies every file referenced by the <init_from> elements of the image library into
+//the directory subdir beneath dir, and rewrites the references to be relative to dir, where the
+//document is then expected to be written. References are resolved as by ResolvePath, with location
+//the path the document was loaded from. Files which cannot be found there, as with absolute paths
+//from another machine, are looked for by name in the directory of location.
+//Missing files are reported rather than failing the packaging; embedded images are left as they are.
+//The xml:base of the document is cleared, since the packaged references are relative to the document.
+func (collada *Collada) PackageImages(location, dir, subdir string) (*PackageReport, error) {
+	return collada.PackageImagesWith(location, subdir, func(name string, data []byte) error {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(filename, data, 0644)
+	})
+}
+
+//PackageImagesToZip is PackageImages writing the files into a zip archive, relative to its root.
+func (collada *Collada) PackageImagesToZip(location string, archive *zip.Writer, subdir string) (*PackageReport, error) {
+	return collada.PackageImagesWith(location, subdir, func(name string, data []byte) error {
+		w, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+}
+
+//PackageImagesWith is PackageImages writing each file through write, given its slash separated name
+//relative to the document. Files with the same name from different sources are given distinct names.
+func (collada *Collada) PackageImagesWith(location, subdir string, write func(name string, data []byte) error) (*PackageReport, error) {
+	report := &PackageReport{}
+	written := make(map[string]Uri)
+	names := make(map[string]bool)
+	for _, library := range collada.LibraryImages {
+		for _, image := range library.Image {
+			for _, init := range image.initFroms() {
+				uri := init.Uri()
+				if uri == "" {
+					continue
+				}
+				source, data, relocated, err := collada.readReference(uri, location)
+				if err != nil {
+					report.Missing = append(report.Missing, &MissingImage{Image: image, Uri: uri, Err: err})
+					continue
+				}
+				packaged, ok := written[source]
+				if !ok {
+					name := uniqueName(path.Join(filepath.ToSlash(subdir), imageFileName(source)), names)
+					if err := write(name, data); err != nil {
+						return report, err
+					}
+					packaged = Uri((&url.URL{Path: name}).String())
+					written[source] = packaged
+				}
+				init.SetUri(packaged)
+				report.Packaged = append(report.Packaged, &PackagedImage{Image: image, Source: source, Uri: packaged, Relocated: relocated})
+			}
+		}
+	}
+	return report, collada.clearBase(report.Missing)
+}
+
+//clearBase removes the xml:base of the document, folding it into the references of missing images
+//so that they still resolve to the same files.
+func (collada *Collada) clearBase(missing []*MissingImage) error {
+	if collada.Base == "" {
+		return nil
+	}
+	base, err := url.Parse(string(collada.Base))
+	if err != nil {
+		return err
+	}
+	for _, m := range missing {
+		ref, err := url.Parse(string(m.Uri))
+		if err != nil || ref.IsAbs() || ref.Host != "" || strings.HasPrefix(ref.Path, "/") {
+			continue
+		}
+		var folded *url.URL
+		if base.IsAbs() || base.Host != "" || strings.HasPrefix(base.Path, "/") {
+			folded = base.ResolveReference(ref)
+		} else {
+			//ResolveReference roots relative paths, so both are joined to stay relative to the document.
+			folded = &url.URL{Path: path.Join(path.Dir(base.Path+"_"), ref.Path), RawQuery: ref.RawQuery, Fragment: ref.Fragment}
+		}
+		for _, init := range m.Image.initFroms() {
+			if init.Uri() == m.Uri {
+				init.SetUri(Uri(folded.String()))
+			}
+		}
+	}
+	collada.Base = ""
+	return nil
+}
+
+//initFroms returns every <init_from> element of an image.
+func (image *Image) initFroms() []*InitFrom {
+	inits := []*InitFrom{}
+	if image.InitFrom != nil {
+		inits = append(inits, image.InitFrom)
+	}
+	if image.Create2d != nil {
+		inits = append(inits, image.Create2d.InitFrom...)
+	}
+	if image.Create3d != nil {
+		inits = append(inits, image.Create3d.InitFrom...)
+	}
+	if image.CreateCube != nil {
+		inits = append(inits, image.CreateCube.InitFrom...)
+	}
+	return inits
+}
+
+//readReference reads a referenced file, falling back to a file of the same name beside the document.
+func (collada *Collada) readReference(uri Uri, location string) (string, []byte, bool, error) {
+	source, err := collada.ResolvePath(uri, location)
+	if err != nil {
+		return "", nil, false, err
+	}
+	data, err := os.ReadFile(source)
+	if err == nil {
+		return source, data, false, nil
+	}
+	dir := "."
+	if location != "" {
+		dir = filepath.Dir(location)
+	}
+	fallback := filepath.Join(dir, imageFileName(source))
+	if fallback == source {
+		return "", nil, false, err
+	}
+	if data, fallbackErr := os.ReadFile(fallback); fallbackErr == nil {
+		return fallback, data, true, nil
+	}
+	return "", nil, false, err
+}
+
+//imageFileName returns the last element of a path written with either kind of separator.
+func imageFileName(source string) string {
+	if i := strings.LastIndexAny(source, `/\`); i >= 0 {
+		return source[i+1:]
+	}
+	return source
+}
+
+//uniqueName returns name, or name with a numeric suffix before its extension if it is already used.
+func uniqueName(name string, used map[string]bool) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	unique := name
+	for i := 1; used[unique]; i++ {
+		unique = base + "-" + strconv.Itoa(i) + ext
+	}
+	used[unique] = true
+	return unique
+}
+
+//String describes a missing image for reporting.
+func (missing *MissingImage) String() string {
+	return fmt.Sprintf("image %q: %s: %v", missing.Image.Id, missing.Uri, missing.Err)
+}
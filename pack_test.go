@@ -0,0 +1,105 @@
+package collada
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPackageImages(t *testing.T) {
+	source := t.TempDir()
+	os.MkdirAll(filepath.Join(source, "maps"), 0755)
+	os.WriteFile(filepath.Join(source, "maps", "wood.png"), []byte("wood"), 0644)
+	os.WriteFile(filepath.Join(source, "metal.png"), []byte("metal"), 0644)
+	os.MkdirAll(filepath.Join(source, "other"), 0755)
+	os.WriteFile(filepath.Join(source, "other", "wood.png"), []byte("other wood"), 0644)
+	collada := &Collada{LibraryImages: []*LibraryImages{{Image: []*Image{
+		{HasId: HasId{"wood"}, InitFrom: &InitFrom{Ref: "maps/wood.png"}},
+		{HasId: HasId{"metal"}, InitFrom: &InitFrom{Value: `C:\Users\artist\Desktop\metal.png`}},
+		{HasId: HasId{"again"}, InitFrom: &InitFrom{Ref: "./maps/wood.png"}},
+		{HasId: HasId{"other"}, InitFrom: &InitFrom{Ref: "other/wood.png"}},
+		{HasId: HasId{"missing"}, InitFrom: &InitFrom{Ref: "missing.png"}},
+		{HasId: HasId{"pixel"}, InitFrom: &InitFrom{Hex: &Hex{Format: "R8", V: HexBinary{1}}}},
+	}}}}
+	output := t.TempDir()
+	report, err := collada.PackageImages(filepath.Join(source, "scene.dae"), output, "textures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Packaged) != 4 || len(report.Missing) != 1 || report.Missing[0].Image.Id != "missing" {
+		t.Fatal("wrong report", len(report.Packaged), len(report.Missing))
+	}
+	if !report.Packaged[1].Relocated {
+		t.Error("windows path not relocated")
+	}
+	images := collada.LibraryImages[0].Image
+	expected := []Uri{"textures/wood.png", "textures/metal.png", "textures/wood.png", "textures/wood-1.png", "missing.png"}
+	for i, uri := range expected {
+		if images[i].InitFrom.Uri() != uri {
+			t.Error("wrong reference", images[i].Id, images[i].InitFrom.Uri())
+		}
+	}
+	if images[1].InitFrom.Ref != "" {
+		t.Error("legacy reference form not kept")
+	}
+	if data, _ := os.ReadFile(filepath.Join(output, "textures", "wood-1.png")); string(data) != "other wood" {
+		t.Error("wrong copy", string(data))
+	}
+	if data, err := collada.ImageData(images[1], filepath.Join(output, "scene.dae")); err != nil || string(data) != "metal" {
+		t.Error("packaged reference does not resolve", err)
+	}
+}
+
+func TestPackageImagesToZip(t *testing.T) {
+	source := t.TempDir()
+	os.WriteFile(filepath.Join(source, "wood grain.png"), []byte("wood"), 0644)
+	collada := &Collada{LibraryImages: []*LibraryImages{{Image: []*Image{
+		{InitFrom: &InitFrom{Ref: "wood%20grain.png"}},
+	}}}}
+	buffer := &bytes.Buffer{}
+	archive := zip.NewWriter(buffer)
+	if _, err := collada.PackageImagesToZip(filepath.Join(source, "scene.dae"), archive, "images"); err != nil {
+		t.Fatal(err)
+	}
+	archive.Close()
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reader.File) != 1 || reader.File[0].Name != "images/wood grain.png" {
+		t.Fatal("wrong archive")
+	}
+	if uri := collada.LibraryImages[0].Image[0].InitFrom.Uri(); uri != "images/wood%20grain.png" {
+		t.Error("wrong reference", uri)
+	}
+}
+
+func TestPackageImagesWithBase(t *testing.T) {
+	source := t.TempDir()
+	os.MkdirAll(filepath.Join(source, "assets", "maps"), 0755)
+	os.WriteFile(filepath.Join(source, "assets", "maps", "wood.png"), []byte("wood"), 0644)
+	collada := &Collada{Base: "assets/", LibraryImages: []*LibraryImages{{Image: []*Image{
+		{HasId: HasId{"wood"}, InitFrom: &InitFrom{Ref: "maps/wood.png"}},
+		{HasId: HasId{"missing"}, InitFrom: &InitFrom{Ref: "maps/missing.png"}},
+	}}}}
+	output := t.TempDir()
+	report, err := collada.PackageImages(filepath.Join(source, "scene.dae"), output, "textures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Packaged) != 1 || len(report.Missing) != 1 {
+		t.Fatal("wrong report", len(report.Packaged), len(report.Missing))
+	}
+	if collada.Base != "" {
+		t.Error("base not cleared", collada.Base)
+	}
+	images := collada.LibraryImages[0].Image
+	if data, err := collada.ImageData(images[0], filepath.Join(output, "scene.dae")); err != nil || string(data) != "wood" {
+		t.Error("packaged reference does not resolve", err)
+	}
+	if uri := images[1].InitFrom.Uri(); uri != "assets/maps/missing.png" {
+		t.Error("base not folded into missing reference", uri)
+	}
+}
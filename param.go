@@ -0,0 +1,402 @@
+package collada
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//ParamScope resolves the parameter references made within a technique of an effect,
+//as instantiated by a material. Material and Technique may be nil to resolve at the scope of the effect alone.
+type ParamScope struct {
+	Material  *Material
+	Effect    *Effect
+	Technique *TechniqueFx
+	collada   *Collada
+}
+
+//ParamScope returns the scope of parameter references made within a technique of the effect a material instantiates.
+func (collada *Collada) ParamScope(material *Material, technique *TechniqueFx) (*ParamScope, error) {
+	id, ok := material.InstanceEffect.Url.Id()
+	if !ok {
+		return nil, fmt.Errorf("collada: material %q does not reference an effect", material.Id)
+	}
+	effect := collada.FindEffect(id)
+	if effect == nil {
+		return nil, fmt.Errorf("collada: material %q references missing effect %q", material.Id, id)
+	}
+	return &ParamScope{Material: material, Effect: effect, Technique: technique, collada: collada}, nil
+}
+
+//newparams returns the parameters declared by each enclosing scope of the technique, innermost first.
+func (scope *ParamScope) newparams() [][]*Newparam {
+	scopes := [][]*Newparam{}
+	if scope.Technique != nil {
+		scopes = append(scopes, scope.Technique.Newparam)
+		if profile := scope.Effect.techniqueProfile(scope.Technique); profile != nil {
+			scopes = append(scopes, profile.Newparam)
+		}
+	}
+	return append(scopes, scope.Effect.Newparam)
+}
+
+//effectProfile holds the parameters and techniques of a profile of an effect.
+type effectProfile struct {
+	*HasNewparam
+	*HasTechniqueFx
+}
+
+//profiles returns every profile of an effect which declares techniques.
+func (effect *Effect) profiles() []effectProfile {
+	profiles := []effectProfile{}
+	if p := effect.ProfileCommon; p != nil {
+		profiles = append(profiles, effectProfile{&p.HasNewparam, &p.HasTechniqueFx})
+	}
+	if p := effect.ProfileGlsl; p != nil {
+		profiles = append(profiles, effectProfile{&p.HasNewparam, &p.HasTechniqueFx})
+	}
+	if p := effect.ProfileGles; p != nil {
+		profiles = append(profiles, effectProfile{&p.HasNewparam, &p.HasTechniqueFx})
+	}
+	if p := effect.ProfileGles2; p != nil {
+		profiles = append(profiles, effectProfile{&p.HasNewparam, &p.HasTechniqueFx})
+	}
+	if p := effect.ProfileCg; p != nil {
+		profiles = append(profiles, effectProfile{&p.HasNewparam, &p.HasTechniqueFx})
+	}
+	return profiles
+}
+
+//techniqueProfile returns the parameters of the profile of an effect which declares a technique, or nil if none does.
+func (effect *Effect) techniqueProfile(technique *TechniqueFx) *HasNewparam {
+	for _, p := range effect.profiles() {
+		for _, t := range p.TechniqueFx {
+			if t == technique {
+				return p.HasNewparam
+			}
+		}
+	}
+	return nil
+}
+
+//FindTechnique returns the first technique of any profile of an effect with the given sid, or nil if there is none.
+func (effect *Effect) FindTechnique(sid string) *TechniqueFx {
+	for _, p := range effect.profiles() {
+		for _, t := range p.TechniqueFx {
+			if t.Sid == sid {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+//Param returns the value of the parameter with the given sid: the value set by the material's <instance_effect>
+//if there is one, or else that of the innermost <newparam> declaring it.
+func (scope *ParamScope) Param(ref string) (*ParamValue, error) {
+	if scope.Material != nil {
+		for _, setparam := range scope.Material.InstanceEffect.Setparam {
+			if setparam.Ref == ref {
+				return &setparam.ParamValue, nil
+			}
+		}
+	}
+	for _, newparams := range scope.newparams() {
+		for _, newparam := range newparams {
+			if newparam.Sid == ref {
+				return &newparam.ParamValue, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("collada: effect %q has no parameter %q", scope.Effect.Id, ref)
+}
+
+//Sampler returns the sampler declared by the parameter with the given sid.
+func (scope *ParamScope) Sampler(ref string) (*FxSamplerCommon, error) {
+	value, err := scope.Param(ref)
+	if err != nil {
+		return nil, err
+	}
+	sampler := value.Sampler()
+	if sampler == nil {
+		return nil, fmt.Errorf("collada: parameter %q is not a sampler", ref)
+	}
+	return sampler, nil
+}
+
+//Image returns the image a sampler reads, through either its <instance_image> or the <surface> parameter it names.
+func (scope *ParamScope) Image(sampler *FxSamplerCommon) (*Image, error) {
+	var id Id
+	switch {
+	case sampler.InstanceImage != nil:
+		var ok bool
+		if id, ok = sampler.InstanceImage.Url.Id(); !ok {
+			return nil, fmt.Errorf("collada: sampler references external image %q", sampler.InstanceImage.Url)
+		}
+	case sampler.Source != "":
+		value, err := scope.Param(sampler.Source)
+		if err != nil {
+			return nil, err
+		}
+		if value.Surface == nil || len(value.Surface.InitFrom) == 0 {
+			return nil, fmt.Errorf("collada: sampler source %q is not an initialized surface", sampler.Source)
+		}
+		id = value.Surface.InitFrom[0].Value
+	default:
+		return nil, fmt.Errorf("collada: sampler has no image")
+	}
+	image := scope.collada.FindImage(id)
+	if image == nil {
+		return nil, fmt.Errorf("collada: sampler references missing image %q", id)
+	}
+	return image, nil
+}
+
+//Texture follows the sampler named by a <texture> element to the image it reads.
+func (scope *ParamScope) Texture(texture *Texture) (*FxSamplerCommon, *Image, error) {
+	sampler, err := scope.Sampler(texture.Texture)
+	if err != nil {
+		return nil, nil, err
+	}
+	image, err := scope.Image(sampler)
+	return sampler, image, err
+}
+
+//Sampler returns the sampler held by a parameter value, or nil if it is not a sampler.
+func (value *ParamValue) Sampler() *FxSamplerCommon {
+	switch {
+	case value.Sampler1D != nil:
+		return &value.Sampler1D.FxSamplerCommon
+	case value.Sampler2D != nil:
+		return &value.Sampler2D.FxSamplerCommon
+	case value.Sampler3D != nil:
+		return &value.Sampler3D.FxSamplerCommon
+	case value.SamplerCube != nil:
+		return &value.SamplerCube.FxSamplerCommon
+	case value.SamplerRect != nil:
+		return &value.SamplerRect.FxSamplerCommon
+	case value.SamplerDepth != nil:
+		return &value.SamplerDepth.FxSamplerCommon
+	}
+	return nil
+}
+
+//paramFields maps the element name of each type of parameter value to the index of its field in ParamValue.
+var paramFields = func() map[string]int {
+	fields := make(map[string]int)
+	t := reflect.TypeOf(ParamValue{})
+	for i := 0; i < t.NumField(); i++ {
+		fields[strings.Split(t.Field(i).Tag.Get("xml"), ",")[0]] = i
+	}
+	return fields
+}()
+
+//field returns the field of a parameter value which is set, with its element name.
+func (value *ParamValue) field() (reflect.Value, string) {
+	v := reflect.ValueOf(value).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Field(i).IsNil() {
+			return v.Field(i), strings.Split(v.Type().Field(i).Tag.Get("xml"), ",")[0]
+		}
+	}
+	return reflect.Value{}, ""
+}
+
+//Type returns the type of a parameter value, named as its element such as "float3" or "sampler2D",
+//or the empty string if no value is set.
+func (value *ParamValue) Type() string {
+	_, name := value.field()
+	return name
+}
+
+//paramSize returns the number of components of a numeric type such as "int3" or "float4x4", or 0 if it is not numeric.
+func paramSize(t string) int {
+	var dims string
+	switch {
+	case strings.HasPrefix(t, "bool"):
+		dims = t[len("bool"):]
+	case strings.HasPrefix(t, "int"):
+		dims = t[len("int"):]
+	case strings.HasPrefix(t, "float"):
+		dims = t[len("float"):]
+	default:
+		return 0
+	}
+	size := 1
+	for _, dim := range strings.Split(dims, "x") {
+		if dim == "" {
+			continue
+		}
+		n, err := strconv.Atoi(dim)
+		if err != nil {
+			return 0
+		}
+		size *= n
+	}
+	return size
+}
+
+//Floats returns the components of a numeric parameter value, with booleans as 0 or 1, or nil if it is not numeric.
+//Matrices are in row-major order.
+func (value *ParamValue) Floats() []float64 {
+	field, _ := value.field()
+	if !field.IsValid() {
+		return nil
+	}
+	switch v := field.Interface().(type) {
+	case *bool:
+		if *v {
+			return []float64{1}
+		}
+		return []float64{0}
+	case *int:
+		return []float64{float64(*v)}
+	case *float64:
+		return []float64{*v}
+	case *Bools:
+		floats := make([]float64, len(v.V))
+		for i, b := range v.V {
+			if b {
+				floats[i] = 1
+			}
+		}
+		return floats
+	case *Ints:
+		floats := make([]float64, len(v.V))
+		for i, n := range v.V {
+			floats[i] = float64(n)
+		}
+		return floats
+	case interface{ F() []float64 }:
+		return v.F()
+	}
+	return nil
+}
+
+//NewParamValue returns a numeric parameter value of the given type, such as "bool2", "int" or "float3x3".
+//Components are rounded for integer types and compared with zero for booleans; their number must match the type.
+func NewParamValue(t string, components ...float64) (*ParamValue, error) {
+	index, ok := paramFields[t]
+	size := paramSize(t)
+	if !ok || size == 0 {
+		return nil, fmt.Errorf("collada: %q is not a numeric parameter type", t)
+	}
+	if len(components) != size {
+		return nil, fmt.Errorf("collada: %s parameter needs %d components, not %d", t, size, len(components))
+	}
+	value := &ParamValue{}
+	field := reflect.ValueOf(value).Elem().Field(index)
+	element := reflect.New(field.Type().Elem())
+	switch v := element.Interface().(type) {
+	case *bool:
+		*v = components[0] != 0
+	case *int:
+		*v = int(math.Round(components[0]))
+	case *float64:
+		*v = components[0]
+	case *Bools:
+		v.V = make(BoolValues, size)
+		for i, c := range components {
+			v.V[i] = c != 0
+		}
+	case *Ints:
+		v.V = make(IntValues, size)
+		for i, c := range components {
+			v.V[i] = int32(math.Round(c))
+		}
+	default:
+		element.Elem().FieldByName("V").Set(reflect.ValueOf(FloatValues(append([]float64{}, components...))))
+	}
+	field.Set(element)
+	return value, nil
+}
+
+//SetFloats replaces a numeric parameter value, keeping its type.
+func (value *ParamValue) SetFloats(components ...float64) error {
+	replaced, err := NewParamValue(value.Type(), components...)
+	if err != nil {
+		return err
+	}
+	*value = *replaced
+	return nil
+}
+
+//UnmarshalXML decodes the elements of an array, whatever their type.
+func (array *Array) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return array.unmarshalXML(d, start, d.DecodeElement)
+}
+
+//unmarshalXML decodes the children through decode.
+func (array *Array) unmarshalXML(d *xml.Decoder, start xml.StartElement, decode func(v interface{}, start *xml.StartElement) error) error {
+	*array = Array{}
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "length":
+			length, err := strconv.ParseUint(attr.Value, 10, 32)
+			if err != nil {
+				return err
+			}
+			array.Length = uint(length)
+		case "resizable":
+			resizable, err := strconv.ParseBool(attr.Value)
+			if err != nil {
+				return err
+			}
+			array.Resizable = &resizable
+		}
+	}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			index, ok := paramFields[t.Name.Local]
+			if !ok {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			value := &ParamValue{}
+			field := reflect.ValueOf(value).Elem().Field(index)
+			field.Set(reflect.New(field.Type().Elem()))
+			if err := decode(field.Interface(), &t); err != nil {
+				return err
+			}
+			array.Element = append(array.Element, value)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+//MarshalXML encodes the elements of an array in order.
+func (array Array) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return array.marshalXML(e, start, e.EncodeElement)
+}
+
+//marshalXML encodes the children through encode.
+func (array Array) marshalXML(e *xml.Encoder, start xml.StartElement, encode func(v interface{}, start xml.StartElement) error) error {
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "length"}, Value: strconv.FormatUint(uint64(array.Length), 10)})
+	if array.Resizable != nil {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "resizable"}, Value: strconv.FormatBool(*array.Resizable)})
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, element := range array.Element {
+		field, name := element.field()
+		if !field.IsValid() {
+			continue
+		}
+		if err := encode(field.Interface(), xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
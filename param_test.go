@@ -0,0 +1,201 @@
+package collada
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const paramDocument = `<?xml version="1.0" encoding="utf-8"?>
+<COLLADA xmlns="http://www.collada.org/2008/03/COLLADASchema" version="1.5.0">
+  <library_images>
+    <image id="wood"><init_from><ref>wood.png</ref></init_from></image>
+    <image id="stone"><init_from>stone.png</init_from></image>
+  </library_images>
+  <library_effects>
+    <effect id="wood-fx">
+      <newparam sid="tint"><float4>1 0.5 0.25 1</float4></newparam>
+      <profile_COMMON>
+        <newparam sid="wood-sampler">
+          <sampler2D>
+            <instance_image url="#wood"/>
+            <wrap_s>MIRROR</wrap_s>
+            <minfilter>LINEAR_MIPMAP_LINEAR</minfilter>
+          </sampler2D>
+        </newparam>
+        <newparam sid="gloss"><float>10</float></newparam>
+        <technique sid="common">
+          <phong>
+            <ambient><param ref="tint"/></ambient>
+            <diffuse><texture texture="wood-sampler" texcoord="UVMap"/></diffuse>
+            <shininess><param ref="gloss"/></shininess>
+          </phong>
+        </technique>
+      </profile_COMMON>
+    </effect>
+    <effect id="stone-fx">
+      <profile_COMMON>
+        <technique sid="common">
+          <newparam sid="stone-surface"><surface type="2D"><init_from>stone</init_from></surface></newparam>
+          <newparam sid="stone-sampler"><sampler2D><source>stone-surface</source><wrap_s>CLAMP</wrap_s></sampler2D></newparam>
+          <lambert>
+            <diffuse><texture texture="stone-sampler" texcoord="UVMap"/></diffuse>
+          </lambert>
+        </technique>
+      </profile_COMMON>
+    </effect>
+  </library_effects>
+  <library_materials>
+    <material id="wood"><instance_effect url="#wood-fx"/></material>
+    <material id="pale-wood">
+      <instance_effect url="#wood-fx">
+        <setparam ref="tint"><float3>0.5 0.5 0.5</float3></setparam>
+      </instance_effect>
+    </material>
+    <material id="stone"><instance_effect url="#stone-fx"/></material>
+  </library_materials>
+</COLLADA>`
+
+func TestParamScope(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(paramDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wood := collada.CommonMaterial(collada.FindMaterial("wood"))
+	if ambient, ok := wood.Ambient.RGBA(); !ok || ambient != [4]float64{1, 0.5, 0.25, 1} {
+		t.Error("wrong effect parameter", ambient)
+	}
+	if shininess, ok := wood.Shininess.Float(); !ok || shininess != 10 {
+		t.Error("wrong profile parameter", shininess)
+	}
+	sampler, image, err := wood.Scope.Texture(wood.Diffuse.Texture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image.Id != "wood" || sampler.WrapS != WrapMirror || sampler.Minfilter != FilterLinearMipmapLinear {
+		t.Error("wrong sampler", image.Id, sampler.WrapS, sampler.Minfilter)
+	}
+
+	wood.Ambient.Color[0], *wood.Shininess.Value = 0, 0
+	if tint, _ := wood.Scope.Param("tint"); tint.Floats()[0] != 1 {
+		t.Error("resolved color shares its values with the parameter")
+	}
+	if gloss, _ := wood.Scope.Param("gloss"); gloss.Floats()[0] != 10 {
+		t.Error("resolved float shares its value with the parameter")
+	}
+
+	pale := collada.CommonMaterial(collada.FindMaterial("pale-wood"))
+	if ambient, ok := pale.Ambient.RGBA(); !ok || ambient != [4]float64{0.5, 0.5, 0.5, 1} {
+		t.Error("setparam not applied", ambient)
+	}
+
+	stone := collada.CommonMaterial(collada.FindMaterial("stone"))
+	sampler, image, err = stone.Scope.Texture(stone.Diffuse.Texture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image.Id != "stone" || sampler.WrapS != WrapClamp {
+		t.Error("wrong surface sampler", image.Id, sampler.WrapS)
+	}
+	if _, err := stone.Scope.Param("tint"); err == nil {
+		t.Error("parameter resolved outside its effect")
+	}
+	if _, err := wood.Scope.Sampler("tint"); err == nil {
+		t.Error("color resolved as a sampler")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"<source>stone-surface</source>", `<instance_image url="#wood">`, "<float3>0.5 0.5 0.5</float3>", `<surface type="2D">`} {
+		if !strings.Contains(buf.String(), s) {
+			t.Error("not exported", s)
+		}
+	}
+}
+
+const typedParamDocument = `<?xml version="1.0" encoding="utf-8"?>
+<COLLADA xmlns="http://www.collada.org/2008/03/COLLADASchema" version="1.5.0">
+  <library_effects>
+    <effect id="skin-fx">
+      <profile_CG>
+        <newparam sid="world">
+          <annotate name="UIName"><string>World</string></annotate>
+          <annotate name="UIVisible"><bool>false</bool></annotate>
+          <semantic>WORLD</semantic>
+          <modifier>UNIFORM</modifier>
+          <float4x4>1 0 0 0 0 1 0 0 0 0 1 0 0 0 0 1</float4x4>
+        </newparam>
+        <newparam sid="mask"><bool3>true false true</bool3></newparam>
+        <newparam sid="offsets">
+          <array length="2" resizable="true"><float2>0 1</float2><float2>2 3</float2></array>
+        </newparam>
+        <newparam sid="light">
+          <usertype typename="Light"><setparam ref="intensity"><float>2</float></setparam></usertype>
+        </newparam>
+        <newparam sid="mode"><enum>ADD</enum></newparam>
+        <technique sid="default"/>
+      </profile_CG>
+    </effect>
+  </library_effects>
+</COLLADA>`
+
+func TestParamValues(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(typedParamDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(collada *Collada) {
+		params := collada.FindEffect("skin-fx").ProfileCg.Newparam
+		world := params[0]
+		if world.Type() != "float4x4" || len(world.Floats()) != 16 || world.Semantic.Value != "WORLD" || world.Modifier.Value != ModifierUniform {
+			t.Error("wrong matrix parameter", world.Type())
+		}
+		if len(world.Annotate) != 2 || *world.Annotate[0].String != "World" || world.Annotate[1].Type() != "bool" {
+			t.Error("wrong annotations")
+		}
+		if mask := params[1].Floats(); params[1].Type() != "bool3" || len(mask) != 3 || mask[1] != 0 || mask[2] != 1 {
+			t.Error("wrong vector parameter", mask)
+		}
+		array := params[2].Array
+		if array == nil || array.Length != 2 || !*array.Resizable || len(array.Element) != 2 || array.Element[1].Floats()[0] != 2 {
+			t.Fatal("wrong array parameter")
+		}
+		if usertype := params[3].Usertype; usertype.Typename != "Light" || *usertype.Setparam[0].Float != 2 {
+			t.Error("wrong usertype parameter")
+		}
+		if params[4].Type() != "enum" || params[4].Floats() != nil {
+			t.Error("wrong enum parameter")
+		}
+	}
+	check(collada)
+
+	buf := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadDocumentFromReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(reloaded)
+
+	mask := &reloaded.FindEffect("skin-fx").ProfileCg.Newparam[1].ParamValue
+	if err := mask.SetFloats(0, 1, 0); err != nil || mask.Bool3.V[0] || !mask.Bool3.V[1] {
+		t.Error("wrong edited vector", err)
+	}
+	if err := mask.SetFloats(1); err == nil {
+		t.Error("wrong number of components accepted")
+	}
+	value, err := NewParamValue("int2", 1.6, -2)
+	if err != nil || value.Type() != "int2" || value.Int2.V[0] != 2 || value.Int2.V[1] != -2 {
+		t.Error("wrong new value", err)
+	}
+	if value, err := NewParamValue("float2x3", 1, 2, 3, 4, 5, 6); err != nil || value.Float2x3.F()[5] != 6 {
+		t.Error("wrong new matrix", err)
+	}
+	if _, err := NewParamValue("sampler2D"); err == nil {
+		t.Error("sampler created as a numeric value")
+	}
+}
@@ -0,0 +1,127 @@
+package collada
+
+import (
+	"fmt"
+	"strings"
+)
+
+//PhysicsBody is a rigid body instanced by a physics scene, with the properties set by its
+//<instance_rigid_body> taking precedence over those of its <rigid_body>.
+type PhysicsBody struct {
+	Model    *PhysicsModel
+	Instance *InstanceRigidBody
+	Body     *RigidBody
+	//Node is the node moved by the body, or nil if its target is not in the document.
+	Node     *Node
+	Dynamic  bool
+	Mass     *float64
+	Material *PhysicsMaterial
+	Shapes   []*Shape
+}
+
+//PhysicsBodies resolves the rigid bodies instanced by a physics scene, including those of nested physics models.
+func (collada *Collada) PhysicsBodies(scene *PhysicsScene) ([]*PhysicsBody, error) {
+	bodies := []*PhysicsBody{}
+	var add func(instances []*InstancePhysicsModel, depth int) error
+	add = func(instances []*InstancePhysicsModel, depth int) error {
+		if depth > 16 {
+			return fmt.Errorf("collada: physics scene %q nests physics models too deeply", scene.Id)
+		}
+		for _, instance := range instances {
+			id, ok := instance.Url.Id()
+			if !ok {
+				return fmt.Errorf("collada: instance_physics_model references external model %q", instance.Url)
+			}
+			model := collada.FindPhysicsModel(id)
+			if model == nil {
+				return fmt.Errorf("collada: instance_physics_model references missing model %q", id)
+			}
+			for _, instanceBody := range instance.InstanceRigidBody {
+				body, err := collada.physicsBody(model, instanceBody)
+				if err != nil {
+					return err
+				}
+				bodies = append(bodies, body)
+			}
+			if err := add(model.InstancePhysicsModel, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := add(scene.InstancePhysicsModel, 0); err != nil {
+		return nil, err
+	}
+	return bodies, nil
+}
+
+func (collada *Collada) physicsBody(model *PhysicsModel, instance *InstanceRigidBody) (*PhysicsBody, error) {
+	sid := instance.Body[strings.LastIndex(instance.Body, "/")+1:]
+	var body *RigidBody
+	for _, b := range model.RigidBody {
+		if b.Sid == sid || string(b.Id) == sid {
+			body = b
+		}
+	}
+	if body == nil {
+		return nil, fmt.Errorf("collada: physics model %q has no rigid body %q", model.Id, instance.Body)
+	}
+	resolved := &PhysicsBody{Model: model, Instance: instance, Body: body, Dynamic: true}
+	if id, ok := instance.Target.Id(); ok {
+		resolved.Node = collada.FindNode(id)
+	}
+	for _, common := range []*RigidBodyTechniqueCommon{&body.TechniqueCommon, &instance.TechniqueCommon.RigidBodyTechniqueCommon} {
+		if common.Dynamic != nil {
+			resolved.Dynamic = common.Dynamic.Value
+		}
+		if common.Mass != nil {
+			mass := common.Mass.Value
+			resolved.Mass = &mass
+		}
+		if material, err := collada.physicsMaterial(common.InstancePhysicsMaterial, common.PhysicsMaterial); err != nil {
+			return nil, err
+		} else if material != nil {
+			resolved.Material = material
+		}
+		if len(common.Shape) > 0 {
+			resolved.Shapes = common.Shape
+		}
+	}
+	return resolved, nil
+}
+
+//ShapeMaterial returns the physics material of a shape of the body, falling back to that of the body.
+func (collada *Collada) ShapeMaterial(body *PhysicsBody, shape *Shape) (*PhysicsMaterial, error) {
+	material, err := collada.physicsMaterial(shape.InstancePhysicsMaterial, shape.PhysicsMaterial)
+	if err != nil || material != nil {
+		return material, err
+	}
+	return body.Material, nil
+}
+
+func (collada *Collada) physicsMaterial(instance *InstancePhysicsMaterial, material *PhysicsMaterial) (*PhysicsMaterial, error) {
+	if material != nil || instance == nil {
+		return material, nil
+	}
+	if id, ok := instance.Url.Id(); ok {
+		if material := collada.FindPhysicsMaterial(id); material != nil {
+			return material, nil
+		}
+	}
+	return nil, fmt.Errorf("collada: instance_physics_material references missing material %q", instance.Url)
+}
+
+//Mat4 returns the transform of the shape relative to its rigid body.
+func (shape *Shape) Mat4() Mat4 {
+	return shape.Transform.Mat4()
+}
+
+//Mat4 returns the transform of the center of mass relative to the rigid body.
+func (frame *MassFrame) Mat4() Mat4 {
+	return frame.Transform.Mat4()
+}
+
+//Mat4 returns the transform of the constraint frame relative to the rigid body it is attached to.
+func (attachment *ConstraintAttachment) Mat4() Mat4 {
+	return attachment.Transform.Mat4()
+}
@@ -0,0 +1,177 @@
+package collada
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+const physicsDocument = `<?xml version="1.0" encoding="utf-8"?>
+<COLLADA xmlns="http://www.collada.org/2008/03/COLLADASchema" version="1.5.0">
+  <library_physics_materials>
+    <physics_material id="rubber">
+      <technique_common>
+        <dynamic_friction>0.8</dynamic_friction>
+        <restitution sid="bounce">0.6</restitution>
+        <static_friction>1</static_friction>
+      </technique_common>
+    </physics_material>
+  </library_physics_materials>
+  <library_force_fields>
+    <force_field id="wind">
+      <technique profile="custom"><direction>1 0 0</direction></technique>
+    </force_field>
+  </library_force_fields>
+  <library_physics_models>
+    <physics_model id="pendulum">
+      <rigid_body sid="ground">
+        <technique_common>
+          <dynamic>false</dynamic>
+          <shape><plane><equation>0 1 0 0</equation></plane></shape>
+        </technique_common>
+      </rigid_body>
+      <rigid_body sid="bob">
+        <technique_common>
+          <mass>2</mass>
+          <mass_frame><rotate>1 0 0 90</rotate><translate>0 0.5 0</translate></mass_frame>
+          <inertia>1 1 1</inertia>
+          <instance_physics_material url="#rubber"/>
+          <shape>
+            <box><half_extents>0.5 0.5 0.5</half_extents></box>
+            <rotate>0 0 1 90</rotate>
+            <translate>1 0 0</translate>
+          </shape>
+          <shape>
+            <density>3</density>
+            <physics_material><technique_common><restitution>0</restitution></technique_common></physics_material>
+            <capsule><height>2</height><radius>0.25 0.25</radius></capsule>
+          </shape>
+          <shape><instance_geometry url="#hull"/></shape>
+        </technique_common>
+      </rigid_body>
+      <rigid_constraint sid="hinge">
+        <ref_attachment rigid_body="ground"><translate>0 3 0</translate></ref_attachment>
+        <attachment rigid_body="bob"/>
+        <technique_common>
+          <enabled>true</enabled>
+          <limits>
+            <swing_cone_and_twist><min>-45 0 0</min><max>45 0 0</max></swing_cone_and_twist>
+          </limits>
+          <spring><angular><stiffness>10</stiffness><damping>0.5</damping></angular></spring>
+        </technique_common>
+      </rigid_constraint>
+    </physics_model>
+  </library_physics_models>
+  <library_physics_scenes>
+    <physics_scene id="world">
+      <instance_force_field url="#wind"/>
+      <instance_physics_model url="#pendulum">
+        <instance_rigid_body body="ground" target="#ground-node"/>
+        <instance_rigid_body body="bob" target="#bob-node">
+          <technique_common>
+            <velocity>1 0 0</velocity>
+            <mass>4</mass>
+          </technique_common>
+        </instance_rigid_body>
+        <instance_rigid_constraint constraint="hinge"/>
+      </instance_physics_model>
+      <technique_common>
+        <gravity>0 -9.8 0</gravity>
+        <time_step>0.01</time_step>
+      </technique_common>
+    </physics_scene>
+  </library_physics_scenes>
+  <library_visual_scenes>
+    <visual_scene id="visual">
+      <node id="ground-node"/>
+      <node id="bob-node"/>
+    </visual_scene>
+  </library_visual_scenes>
+  <scene>
+    <instance_physics_scene url="#world"/>
+    <instance_visual_scene url="#visual"/>
+  </scene>
+</COLLADA>`
+
+func TestPhysics(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(physicsDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(collada *Collada) {
+		if rubber := collada.FindPhysicsMaterial("rubber"); rubber == nil || rubber.TechniqueCommon.Restitution.Sid != "bounce" || rubber.TechniqueCommon.DynamicFriction.Value != 0.8 {
+			t.Error("wrong physics material")
+		}
+		if wind := collada.FindForceField("wind"); wind == nil || wind.TechniqueCore[0].Profile != "custom" {
+			t.Error("wrong force field")
+		}
+		model := collada.FindPhysicsModel("pendulum")
+		if model == nil || len(model.RigidBody) != 2 || len(model.RigidConstraint) != 1 {
+			t.Fatal("wrong physics model")
+		}
+		bob := model.RigidBody[1].TechniqueCommon
+		if bob.Mass.Value != 2 || bob.Inertia.F()[0] != 1 || len(bob.Shape) != 3 || bob.Shape[0].Box.HalfExtents.F()[1] != 0.5 ||
+			bob.Shape[1].Capsule.Height != 2 || bob.Shape[1].Capsule.Radius.F()[1] != 0.25 || bob.Shape[2].InstanceGeometry.Url != "#hull" {
+			t.Error("wrong rigid body")
+		}
+		near := func(m Mat4, x, y, z float64) bool {
+			px, py, pz := m.TransformPoint(0, 0, 0)
+			return math.Abs(px-x) < 1e-9 && math.Abs(py-y) < 1e-9 && math.Abs(pz-z) < 1e-9
+		}
+		if !near(bob.Shape[0].Mat4(), 0, 1, 0) || !near(bob.MassFrame.Mat4(), 0, 0, 0.5) {
+			t.Error("shape or mass frame transforms not composed in document order")
+		}
+		if model.RigidBody[0].TechniqueCommon.Shape[0].Plane.Equation.F()[1] != 1 {
+			t.Error("wrong plane")
+		}
+		hinge := model.RigidConstraint[0]
+		if hinge.RefAttachment.RigidBody != "ground" || !hinge.TechniqueCommon.Enabled.Value || hinge.TechniqueCommon.Limits.SwingConeAndTwist.Max.F()[0] != 45 ||
+			hinge.TechniqueCommon.Spring.Angular.Damping.Value != 0.5 {
+			t.Error("wrong rigid constraint")
+		}
+		if x, y, z := hinge.RefAttachment.Mat4().TransformPoint(0, 0, 0); x != 0 || y != 3 || z != 0 {
+			t.Error("wrong attachment transform")
+		}
+		scene := collada.FindPhysicsScene("world")
+		if scene == nil || scene.TechniqueCommon.Gravity.F()[1] != -9.8 || scene.TechniqueCommon.TimeStep.Value != 0.01 || scene.InstanceForceField[0].Url != "#wind" {
+			t.Error("wrong physics scene")
+		}
+		if collada.Scene.InstancePhysicsScene[0].Url != "#world" {
+			t.Error("wrong physics scene instance")
+		}
+	}
+	check(collada)
+
+	bodies, err := collada.PhysicsBodies(collada.FindPhysicsScene("world"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bodies) != 2 || bodies[0].Dynamic || bodies[0].Node != collada.FindNode("ground-node") {
+		t.Fatal("wrong ground body")
+	}
+	bob := bodies[1]
+	if !bob.Dynamic || *bob.Mass != 4 || bob.Material != collada.FindPhysicsMaterial("rubber") || len(bob.Shapes) != 3 ||
+		bob.Instance.TechniqueCommon.Velocity.F()[0] != 1 {
+		t.Error("wrong bob body")
+	}
+	if *bob.Mass = 5; bob.Instance.TechniqueCommon.Mass.Value != 4 {
+		t.Error("resolved mass shares its value with the document")
+	}
+	if material, err := collada.ShapeMaterial(bob, bob.Shapes[1]); err != nil || material.TechniqueCommon.Restitution.Value != 0 {
+		t.Error("wrong inline shape material")
+	}
+	if material, err := collada.ShapeMaterial(bob, bob.Shapes[0]); err != nil || material != bob.Material {
+		t.Error("wrong shape material fallback")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadDocumentFromReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(reloaded)
+}
@@ -0,0 +1,95 @@
+package collada
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+//ExportPly writes the polygons of a visual scene to filename as PLY.
+func (collada *Collada) ExportPly(filename string, options MeshExportOptions) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return collada.ExportPlyToWriter(file, options)
+}
+
+//ExportPlyToWriter writes the polygons of a visual scene as PLY, baked into world space.
+//Vertices carry normals from NORMAL inputs and 8 bit colors from COLOR inputs when the scene has any.
+func (collada *Collada) ExportPlyToWriter(writer io.Writer, options MeshExportOptions) error {
+	mesh, err := collada.bake(&options)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(writer)
+	format := "ascii"
+	if options.Binary {
+		format = "binary_little_endian"
+	}
+	fmt.Fprintf(w, "ply\nformat %s 1.0\ncomment generated by go-collada\n", format)
+	fmt.Fprintf(w, "element vertex %d\n", len(mesh.vertices))
+	w.WriteString("property float x\nproperty float y\nproperty float z\n")
+	if mesh.normals {
+		w.WriteString("property float nx\nproperty float ny\nproperty float nz\n")
+	}
+	if mesh.colors {
+		w.WriteString("property uchar red\nproperty uchar green\nproperty uchar blue\nproperty uchar alpha\n")
+	}
+	fmt.Fprintf(w, "element face %d\n", len(mesh.vcount))
+	w.WriteString("property list uchar int vertex_indices\nend_header\n")
+	for _, vertex := range mesh.vertices {
+		floats := vertex.position[:]
+		if mesh.normals {
+			floats = append(floats, vertex.normal[:]...)
+		}
+		for i, f := range floats {
+			if options.Binary {
+				binary.Write(w, binary.LittleEndian, float32(f))
+				continue
+			}
+			if i > 0 {
+				w.WriteString(" ")
+			}
+			w.WriteString(formatFloat(f))
+		}
+		if mesh.colors {
+			for _, c := range vertex.color {
+				b := uint8(math.Round(math.Max(0, math.Min(1, c)) * 255))
+				if options.Binary {
+					w.WriteByte(b)
+				} else {
+					fmt.Fprintf(w, " %d", b)
+				}
+			}
+		}
+		if !options.Binary {
+			w.WriteString("\n")
+		}
+	}
+	first := 0
+	for _, n := range mesh.vcount {
+		if n > math.MaxUint8 {
+			return fmt.Errorf("collada: polygon of %d vertices exceeds the PLY list size", n)
+		}
+		corners := mesh.indices[first : first+n]
+		first += n
+		if options.Binary {
+			w.WriteByte(uint8(n))
+			for _, index := range corners {
+				binary.Write(w, binary.LittleEndian, int32(index))
+			}
+			continue
+		}
+		fmt.Fprintf(w, "%d", n)
+		for _, index := range corners {
+			fmt.Fprintf(w, " %d", index)
+		}
+		w.WriteString("\n")
+	}
+	return w.Flush()
+}
@@ -0,0 +1,37 @@
+package collada
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExportPly(t *testing.T) {
+	doc := centimeterTriangle(t)
+	mesh := doc.Collada.LibraryGeometries[0].Geometry[0].Mesh
+	colors := doc.newSource("Triangle-colors", []float64{1, 0, 0, 0, 1, 0, 0, 0, 1}, "R", "G", "B")
+	mesh.Source = append(mesh.Source, colors)
+	triangles := mesh.Triangles[0]
+	triangles.Input = append(triangles.Input, &InputShared{Semantic: "COLOR", Source: colors.Id.Uri()})
+	buffer := &bytes.Buffer{}
+	if err := doc.Collada.ExportPlyToWriter(buffer, MeshExportOptions{Meter: 1}); err != nil {
+		t.Fatal(err)
+	}
+	header, body, ok := strings.Cut(buffer.String(), "end_header\n")
+	if !ok || !strings.Contains(header, "element vertex 3\n") || !strings.Contains(header, "property uchar red\n") {
+		t.Fatal("wrong header", header)
+	}
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	if len(lines) != 4 || lines[1] != "0.01 0 0.02 0 255 0 255" || lines[3] != "3 0 1 2" {
+		t.Error("wrong vertices", lines)
+	}
+
+	buffer.Reset()
+	if err := doc.Collada.ExportPlyToWriter(buffer, MeshExportOptions{Binary: true}); err != nil {
+		t.Fatal(err)
+	}
+	header, body, _ = strings.Cut(buffer.String(), "end_header\n")
+	if !strings.Contains(header, "format binary_little_endian 1.0\n") || len(body) != 3*(12+4)+1+3*4 {
+		t.Error("wrong binary ply", header, len(body))
+	}
+}
@@ -0,0 +1,310 @@
+package collada
+
+import (
+	"fmt"
+)
+
+//Attribute holds the values of one input of a flattened primitive.
+type Attribute struct {
+	Semantic string
+	Set      uint
+	//Size is the number of components of each element of Values.
+	Size   int
+	Values []float64
+	//Indices selects the element of Values used by each polygon corner.
+	Indices []int
+}
+
+//Primitive is a mesh primitive reduced to a list of polygons
+//whose corners index every attribute separately.
+type Primitive struct {
+	//Material is the symbol bound to a material by <instance_material>.
+	Material string
+	//VCount is the number of corners of each polygon.
+	VCount     []int
+	Attributes []*Attribute
+}
+
+//Values returns the values of a source as read by its accessor, along with the number of
+//components of each element. Unnamed accessor params are skipped as the specification requires.
+func (source *Source) Values() ([]float64, int) {
+	var data []float64
+	switch {
+	case source.FloatArray != nil:
+		data = source.FloatArray.F()
+	case source.IntArray != nil:
+		ints := source.IntArray.V
+		data = make([]float64, len(ints))
+		for i, v := range ints {
+			data[i] = float64(v)
+		}
+	case source.BoolArray != nil:
+		bools := source.BoolArray.V
+		data = make([]float64, len(bools))
+		for i, v := range bools {
+			if v {
+				data[i] = 1
+			}
+		}
+	}
+	if source.TechniqueCommon == nil {
+		return data, 1
+	}
+	accessor := source.TechniqueCommon.Accessor
+	stride := int(accessor.Stride)
+	if stride < 1 {
+		stride = 1
+	}
+	components := []int{}
+	for i, param := range accessor.Param {
+		if param.Name != "" {
+			components = append(components, i)
+		}
+	}
+	if len(accessor.Param) == 0 {
+		for i := 0; i < stride; i++ {
+			components = append(components, i)
+		}
+	}
+	values := make([]float64, 0, accessor.Count*len(components))
+	for i := 0; i < accessor.Count; i++ {
+		base := int(accessor.Offset) + i*stride
+		for _, c := range components {
+			values = append(values, floatsAt(data, base+c))
+		}
+	}
+	return values, len(components)
+}
+
+//source returns the source of the mesh with the given reference.
+func (mesh *Mesh) source(uri Uri) (*Source, error) {
+	id, ok := uri.Id()
+	if ok {
+		for _, source := range mesh.Source {
+			if source.Id == id {
+				return source, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("collada: mesh has no source %q", uri)
+}
+
+//attributes resolves the shared inputs of a primitive, expanding the VERTEX input into the inputs of the mesh vertices.
+//It also returns the offset into each corner of the index of every attribute.
+func (mesh *Mesh) attributes(inputs []*InputShared) ([]*Attribute, []int, error) {
+	attributes := []*Attribute{}
+	offsets := []int{}
+	for _, input := range inputs {
+		if input.Semantic == "VERTEX" {
+			for _, vertex := range mesh.Vertices.Input {
+				source, err := mesh.source(vertex.Source)
+				if err != nil {
+					return nil, nil, err
+				}
+				values, size := source.Values()
+				attributes = append(attributes, &Attribute{Semantic: vertex.Semantic, Size: size, Values: values})
+				offsets = append(offsets, int(input.Offset))
+			}
+			continue
+		}
+		source, err := mesh.source(input.Source)
+		if err != nil {
+			return nil, nil, err
+		}
+		values, size := source.Values()
+		attributes = append(attributes, &Attribute{Semantic: input.Semantic, Set: input.Set, Size: size, Values: values})
+		offsets = append(offsets, int(input.Offset))
+	}
+	return attributes, offsets, nil
+}
+
+//newPrimitive indexes the attributes of every corner listed by p.
+func (mesh *Mesh) newPrimitive(material string, inputs []*InputShared, vcount []int, p []int) (*Primitive, error) {
+	attributes, offsets, err := mesh.attributes(inputs)
+	if err != nil {
+		return nil, err
+	}
+	stride := inputStride(inputs)
+	corners := 0
+	for _, n := range vcount {
+		corners += n
+	}
+	if corners*stride > len(p) {
+		return nil, fmt.Errorf("collada: primitive lists %d indices for %d corners", len(p), corners)
+	}
+	for a, attribute := range attributes {
+		count := 0
+		if attribute.Size > 0 {
+			count = len(attribute.Values) / attribute.Size
+		}
+		attribute.Indices = make([]int, corners)
+		for c := range attribute.Indices {
+			index := p[c*stride+offsets[a]]
+			if index < 0 || index >= count {
+				return nil, fmt.Errorf("collada: %s index %d out of range", attribute.Semantic, index)
+			}
+			attribute.Indices[c] = index
+		}
+	}
+	return &Primitive{Material: material, VCount: vcount, Attributes: attributes}, nil
+}
+
+func repeat(n, count int) []int {
+	vs := make([]int, count)
+	for i := range vs {
+		vs[i] = n
+	}
+	return vs
+}
+
+//stripCorners converts a triangle strip or fan of corners into separate triangles,
+//indexing whole corners of the given stride.
+func stripCorners(p []int, stride int, fan bool) []int {
+	n := len(p) / stride
+	corners := []int{}
+	for i := 2; i < n; i++ {
+		a, b, c := i-2, i-1, i
+		if fan {
+			a = 0
+		} else if i%2 == 1 {
+			a, b = b, a
+		}
+		for _, corner := range []int{a, b, c} {
+			corners = append(corners, p[corner*stride:(corner+1)*stride]...)
+		}
+	}
+	return corners
+}
+
+func inputStride(inputs []*InputShared) int {
+	stride := 1
+	for _, input := range inputs {
+		if int(input.Offset)+1 > stride {
+			stride = int(input.Offset) + 1
+		}
+	}
+	return stride
+}
+
+//sharedInputs returns the inputs of every primitive element of the mesh.
+func (mesh *Mesh) sharedInputs() [][]*InputShared {
+	inputs := [][]*InputShared{}
+	for _, p := range mesh.Lines {
+		inputs = append(inputs, p.Input)
+	}
+	for _, p := range mesh.Linestrips {
+		inputs = append(inputs, p.Input)
+	}
+	for _, p := range mesh.Polygons {
+		inputs = append(inputs, p.Input)
+	}
+	for _, p := range mesh.Polylist {
+		inputs = append(inputs, p.Input)
+	}
+	for _, p := range mesh.Triangles {
+		inputs = append(inputs, p.Input)
+	}
+	for _, p := range mesh.Trifans {
+		inputs = append(inputs, p.Input)
+	}
+	for _, p := range mesh.Tristrips {
+		inputs = append(inputs, p.Input)
+	}
+	return inputs
+}
+
+//Primitives flattens the polygonal primitives of a mesh.
+//Triangle strips and fans are split into triangles, holes in <polygons> are ignored and lines are skipped.
+func (mesh *Mesh) Primitives() ([]*Primitive, error) {
+	primitives := []*Primitive{}
+	add := func(material string, inputs []*InputShared, vcount []int, p []int) error {
+		primitive, err := mesh.newPrimitive(material, inputs, vcount, p)
+		if err == nil {
+			primitives = append(primitives, primitive)
+		}
+		return err
+	}
+	for _, triangles := range mesh.Triangles {
+		if triangles.P == nil {
+			continue
+		}
+		p := triangles.P.I()
+		stride := inputStride(triangles.Input)
+		if err := add(triangles.Material, triangles.Input, repeat(3, len(p)/stride/3), p); err != nil {
+			return nil, err
+		}
+	}
+	for _, polylist := range mesh.Polylist {
+		if polylist.P == nil || polylist.VCount == nil {
+			continue
+		}
+		if err := add(polylist.Material, polylist.Input, polylist.VCount.I(), polylist.P.I()); err != nil {
+			return nil, err
+		}
+	}
+	for _, polygons := range mesh.Polygons {
+		stride := inputStride(polygons.Input)
+		vcount := []int{}
+		p := []int{}
+		for _, polygon := range polygons.P {
+			vcount = append(vcount, len(polygon.V)/stride)
+			p = append(p, polygon.I()...)
+		}
+		for _, ph := range polygons.Ph {
+			vcount = append(vcount, len(ph.P.V)/stride)
+			p = append(p, ph.P.I()...)
+		}
+		if err := add(polygons.Material, polygons.Input, vcount, p); err != nil {
+			return nil, err
+		}
+	}
+	for _, trifans := range mesh.Trifans {
+		if trifans.P == nil {
+			continue
+		}
+		p := stripCorners(trifans.P.I(), inputStride(trifans.Input), true)
+		if err := add(trifans.Material, trifans.Input, repeat(3, len(p)/inputStride(trifans.Input)/3), p); err != nil {
+			return nil, err
+		}
+	}
+	for _, tristrips := range mesh.Tristrips {
+		if tristrips.P == nil {
+			continue
+		}
+		p := stripCorners(tristrips.P.I(), inputStride(tristrips.Input), false)
+		if err := add(tristrips.Material, tristrips.Input, repeat(3, len(p)/inputStride(tristrips.Input)/3), p); err != nil {
+			return nil, err
+		}
+	}
+	return primitives, nil
+}
+
+//Attribute returns the attribute with the given semantic and the lowest set, or nil if there is none.
+func (primitive *Primitive) Attribute(semantic string) *Attribute {
+	var found *Attribute
+	for _, attribute := range primitive.Attributes {
+		if attribute.Semantic == semantic && (found == nil || attribute.Set < found.Set) {
+			found = attribute
+		}
+	}
+	return found
+}
+
+//Triangles returns the corners of the primitive's polygons split into triangle fans, three corners per triangle.
+func (primitive *Primitive) Triangles() []int {
+	corners := []int{}
+	first := 0
+	for _, n := range primitive.VCount {
+		for i := 2; i < n; i++ {
+			corners = append(corners, first, first+i-1, first+i)
+		}
+		first += n
+	}
+	return corners
+}
+
+//Element returns the components of the element used by a corner.
+func (attribute *Attribute) Element(corner int) []float64 {
+	i := attribute.Indices[corner] * attribute.Size
+	return attribute.Values[i : i+attribute.Size]
+}
@@ -0,0 +1,72 @@
+package collada
+
+import (
+	"fmt"
+)
+
+//RenderStep is a <render> of an <evaluate_scene> with its references resolved.
+type RenderStep struct {
+	Render *Render
+	//Camera is the node whose camera renders the scene, or nil if the render does not name one.
+	Camera *Node
+	//Material is the material of the screen effect applied by the render, or nil if it renders the scene as it is.
+	Material *Material
+	Effect   *Effect
+	//Technique and Pass are those selected by <technique_override>, or nil if the render does not override them.
+	Technique *TechniqueFx
+	Pass      *Pass
+}
+
+//Enabled reports whether an <evaluate_scene> is to be evaluated, which it is unless disabled explicitly.
+func (evaluate *EvaluateScene) Enabled() bool {
+	return evaluate.Enable == nil || *evaluate.Enable
+}
+
+//RenderSteps resolves the cameras, materials and techniques of each render of an <evaluate_scene>, in order.
+func (collada *Collada) RenderSteps(evaluate *EvaluateScene) ([]*RenderStep, error) {
+	steps := []*RenderStep{}
+	for _, render := range evaluate.Render {
+		step := &RenderStep{Render: render}
+		if render.CameraNode != "" {
+			id, ok := render.CameraNode.Id()
+			if !ok {
+				return nil, fmt.Errorf("collada: render references external camera node %q", render.CameraNode)
+			}
+			if step.Camera = collada.FindNode(id); step.Camera == nil {
+				return nil, fmt.Errorf("collada: render references missing camera node %q", id)
+			}
+		}
+		if instance := render.InstanceMaterial; instance != nil {
+			id, ok := instance.Url.Id()
+			if !ok {
+				return nil, fmt.Errorf("collada: render references external material %q", instance.Url)
+			}
+			if step.Material = collada.FindMaterial(id); step.Material == nil {
+				return nil, fmt.Errorf("collada: render references missing material %q", id)
+			}
+			if id, ok := step.Material.InstanceEffect.Url.Id(); ok {
+				step.Effect = collada.FindEffect(id)
+			}
+			if override := instance.TechniqueOverride; override != nil {
+				if step.Effect == nil {
+					return nil, fmt.Errorf("collada: material %q has no effect to override", step.Material.Id)
+				}
+				if step.Technique = step.Effect.FindTechnique(override.Ref); step.Technique == nil {
+					return nil, fmt.Errorf("collada: effect %q has no technique %q", step.Effect.Id, override.Ref)
+				}
+				if override.Pass != "" {
+					for _, pass := range step.Technique.Pass {
+						if pass.Sid == override.Pass {
+							step.Pass = pass
+						}
+					}
+					if step.Pass == nil {
+						return nil, fmt.Errorf("collada: technique %q has no pass %q", override.Ref, override.Pass)
+					}
+				}
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
@@ -0,0 +1,97 @@
+package collada
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const renderDocument = `<?xml version="1.0" encoding="utf-8"?>
+<COLLADA xmlns="http://www.collada.org/2008/03/COLLADASchema" version="1.5.0">
+  <library_images>
+    <image id="scene-color"><create_2d><size_ratio width="1" height="1"/><mips levels="1" auto_generate="false"/><format><exact>R8G8B8A8</exact></format></create_2d></image>
+  </library_images>
+  <library_effects>
+    <effect id="blur-fx">
+      <newparam sid="source"><sampler2D><instance_image url="#scene-color"/></sampler2D></newparam>
+      <profile_GLSL>
+        <technique sid="blur">
+          <pass sid="horizontal">
+            <evaluate>
+              <color_target index="0"><instance_image url="#scene-color"/></color_target>
+              <depth_target><param ref="depth"/></depth_target>
+              <color_clear index="0">0 0 0 1</color_clear>
+              <depth_clear>1</depth_clear>
+              <stencil_clear>0</stencil_clear>
+              <draw>FULL_SCREEN_QUAD</draw>
+            </evaluate>
+          </pass>
+        </technique>
+      </profile_GLSL>
+    </effect>
+  </library_effects>
+  <library_materials>
+    <material id="blur"><instance_effect url="#blur-fx"/></material>
+  </library_materials>
+  <library_visual_scenes>
+    <visual_scene id="scene">
+      <node id="camera-node"/>
+      <evaluate_scene name="post" enable="true">
+        <render sid="beauty" camera_node="#camera-node">
+          <layer>opaque</layer>
+          <layer>transparent</layer>
+        </render>
+        <render sid="blur">
+          <instance_material url="#blur">
+            <technique_override ref="blur" pass="horizontal"/>
+            <bind semantic="SOURCE" target="scene-color"/>
+          </instance_material>
+        </render>
+      </evaluate_scene>
+    </visual_scene>
+  </library_visual_scenes>
+</COLLADA>`
+
+func TestRenderSteps(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(renderDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	evaluate := collada.FindVisualScene("scene").EvaluateScene[0]
+	if !evaluate.Enabled() {
+		t.Error("evaluate_scene disabled")
+	}
+	steps, err := collada.RenderSteps(evaluate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 2 || steps[0].Camera == nil || steps[0].Camera.Id != "camera-node" || len(steps[0].Render.Layer) != 2 {
+		t.Fatal("wrong scene render")
+	}
+	blur := steps[1]
+	if blur.Material == nil || blur.Effect.Id != "blur-fx" || blur.Technique.Sid != "blur" || blur.Pass == nil {
+		t.Fatal("wrong screen effect render")
+	}
+	target := blur.Pass.Evaluate
+	if target.ColorTarget[0].InstanceImage.Url != "#scene-color" || target.DepthTarget[0].Param.Ref != "depth" {
+		t.Error("wrong render targets")
+	}
+	if target.ColorClear[0].Value[3] != 1 || target.DepthClear[0].Value != 1 || target.Draw.Value != DrawFullScreenQuad {
+		t.Error("wrong clears")
+	}
+
+	evaluate.Render[1].InstanceMaterial.TechniqueOverride.Pass = "vertical"
+	if _, err := collada.RenderSteps(evaluate); err == nil {
+		t.Error("missing pass resolved")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`<render sid="beauty" camera_node="#camera-node">`, "<layer>transparent</layer>", "<color_clear>0 0 0 1</color_clear>", `<technique_override ref="blur" pass="vertical">`} {
+		if !strings.Contains(buf.String(), s) {
+			t.Error("not exported", s)
+		}
+	}
+}
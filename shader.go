@@ -0,0 +1,253 @@
+package collada
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+//UnmarshalXML decodes the <inline> and <import> children of <sources> in document order.
+func (sources *Sources) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*sources = Sources{}
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "entry" {
+			sources.Entry = attr.Value
+		}
+	}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "inline":
+				source := &ShaderSource{}
+				if err := d.DecodeElement(&source.Inline, &t); err != nil {
+					return err
+				}
+				sources.Source = append(sources.Source, source)
+			case "import":
+				var ref struct {
+					Ref string `xml:"ref,attr"`
+				}
+				if err := d.DecodeElement(&ref, &t); err != nil {
+					return err
+				}
+				sources.Source = append(sources.Source, &ShaderSource{Import: ref.Ref})
+			default:
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+//MarshalXML encodes the sources as <inline> and <import> children in order.
+func (sources Sources) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if sources.Entry != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "entry"}, Value: sources.Entry})
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, source := range sources.Source {
+		if source.Import != "" {
+			element := xml.StartElement{
+				Name: xml.Name{Local: "import"},
+				Attr: []xml.Attr{{Name: xml.Name{Local: "ref"}, Value: source.Import}},
+			}
+			if err := e.EncodeElement("", element); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := e.EncodeElement(source.Inline, xml.StartElement{Name: xml.Name{Local: "inline"}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+//ShaderSource returns the source code of a shader of a profile_GLSL technique, concatenating its inline blocks
+//with the <code> blocks of the profile it imports. Imported <include> files are not read and are an error.
+func (profile *ProfileGlsl) ShaderSource(shader *Shader) (string, error) {
+	return shaderSource(shader, profile.Code, profile.Include)
+}
+
+//PassSources returns the source code of each stage of a pass of a profile_GLSL technique.
+func (profile *ProfileGlsl) PassSources(pass *Pass) (map[ShaderStage]string, error) {
+	return passSources(pass, profile.Code, profile.Include)
+}
+
+//ShaderSource returns the source code of a shader of a profile_GLES2 technique, as for profile_GLSL.
+func (profile *ProfileGles2) ShaderSource(shader *Shader) (string, error) {
+	return shaderSource(shader, profile.Code, profile.Include)
+}
+
+//PassSources returns the source code of each stage of a pass of a profile_GLES2 technique.
+func (profile *ProfileGles2) PassSources(pass *Pass) (map[ShaderStage]string, error) {
+	return passSources(pass, profile.Code, profile.Include)
+}
+
+//ShaderSource returns the source code of a shader of a profile_CG technique, as for profile_GLSL.
+func (profile *ProfileCg) ShaderSource(shader *Shader) (string, error) {
+	return shaderSource(shader, profile.Code, profile.Include)
+}
+
+//PassSources returns the source code of each stage of a pass of a profile_CG technique.
+func (profile *ProfileCg) PassSources(pass *Pass) (map[ShaderStage]string, error) {
+	return passSources(pass, profile.Code, profile.Include)
+}
+
+//BridgePath returns the path of the external effect file referenced by a profile_BRIDGE,
+//resolved as by ResolvePath with location the path the document was loaded from.
+func (collada *Collada) BridgePath(profile *ProfileBridge, location string) (string, error) {
+	if profile.Url == "" {
+		return "", fmt.Errorf("collada: profile_BRIDGE %q does not reference a file", profile.Id)
+	}
+	return collada.ResolvePath(profile.Url, location)
+}
+
+func shaderSource(shader *Shader, codes []*Code, includes []*Include) (string, error) {
+	var b strings.Builder
+	for _, source := range shader.Sources.Source {
+		if source.Import == "" {
+			b.WriteString(source.Inline)
+			continue
+		}
+		code, err := importedCode(source.Import, codes, includes)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(code)
+	}
+	return b.String(), nil
+}
+
+//importedCode returns the <code> block of a profile with the given sid.
+func importedCode(sid string, codes []*Code, includes []*Include) (string, error) {
+	for _, code := range codes {
+		if code.Sid == sid {
+			return code.Value, nil
+		}
+	}
+	for _, include := range includes {
+		if include.Sid == sid {
+			return "", fmt.Errorf("collada: shader imports external source %q", include.Url)
+		}
+	}
+	return "", fmt.Errorf("collada: shader imports missing source %q", sid)
+}
+
+func passSources(pass *Pass, codes []*Code, includes []*Include) (map[ShaderStage]string, error) {
+	sources := make(map[ShaderStage]string)
+	if pass.Program == nil {
+		return sources, nil
+	}
+	for _, shader := range pass.Program.Shader {
+		source, err := shaderSource(shader, codes, includes)
+		if err != nil {
+			return nil, err
+		}
+		sources[shader.Stage] = source
+	}
+	return sources, nil
+}
+
+//UnmarshalXML decodes the <texcombiner> and <texenv> stages of a texture pipeline in document order.
+func (pipeline *TexturePipeline) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return pipeline.unmarshalXML(d, start, d.DecodeElement)
+}
+
+//unmarshalXML decodes the children through decode.
+func (pipeline *TexturePipeline) unmarshalXML(d *xml.Decoder, start xml.StartElement, decode func(v interface{}, start *xml.StartElement) error) error {
+	*pipeline = TexturePipeline{}
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "sid" {
+			pipeline.Sid = attr.Value
+		}
+	}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "texcombiner":
+				stage := &TextureStage{Texcombiner: &Texcombiner{}}
+				if err := decode(stage.Texcombiner, &t); err != nil {
+					return err
+				}
+				pipeline.Stage = append(pipeline.Stage, stage)
+			case "texenv":
+				stage := &TextureStage{Texenv: &Texenv{}}
+				if err := decode(stage.Texenv, &t); err != nil {
+					return err
+				}
+				pipeline.Stage = append(pipeline.Stage, stage)
+			case "extra":
+				extra := &Extra{}
+				if err := decode(extra, &t); err != nil {
+					return err
+				}
+				pipeline.Extra = append(pipeline.Extra, extra)
+			default:
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+//MarshalXML encodes the stages of a texture pipeline in order.
+func (pipeline TexturePipeline) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return pipeline.marshalXML(e, start, e.EncodeElement)
+}
+
+//marshalXML encodes the children through encode.
+func (pipeline TexturePipeline) marshalXML(e *xml.Encoder, start xml.StartElement, encode func(v interface{}, start xml.StartElement) error) error {
+	if pipeline.Sid != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "sid"}, Value: pipeline.Sid})
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, stage := range pipeline.Stage {
+		var err error
+		if stage.Texcombiner != nil {
+			err = encode(stage.Texcombiner, xml.StartElement{Name: xml.Name{Local: "texcombiner"}})
+		} else if stage.Texenv != nil {
+			err = encode(stage.Texenv, xml.StartElement{Name: xml.Name{Local: "texenv"}})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, extra := range pipeline.Extra {
+		if err := encode(extra, xml.StartElement{Name: xml.Name{Local: "extra"}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+//TexturePipeline decodes the <texture_pipeline> state of a profile_GLES pass, or returns nil if it sets none.
+func (states *States) TexturePipeline() (*TexturePipeline, error) {
+	var decoded struct {
+		TexturePipeline *TexturePipeline `xml:"texture_pipeline"`
+	}
+	if err := xml.Unmarshal([]byte("<states>"+states.XML+"</states>"), &decoded); err != nil {
+		return nil, err
+	}
+	return decoded.TexturePipeline, nil
+}
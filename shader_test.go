@@ -0,0 +1,257 @@
+package collada
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const glslDocument = `<?xml version="1.0" encoding="utf-8"?>
+<COLLADA xmlns="http://www.collada.org/2008/03/COLLADASchema" version="1.5.0">
+  <library_effects>
+    <effect id="toon-fx">
+      <profile_GLSL platform="PC">
+        <code sid="lighting">float lit(vec3 n, vec3 l) { return max(dot(n, l), 0.0); }
+</code>
+        <include sid="noise" url="noise.glsl"/>
+        <newparam sid="bands"><float>4</float></newparam>
+        <technique sid="default">
+          <pass sid="outline">
+            <states>
+              <cull_face value="FRONT"/>
+              <blend_func><src value="SRC_ALPHA"/><dest value="ONE_MINUS_SRC_ALPHA"/></blend_func>
+            </states>
+            <program>
+              <shader stage="VERTEX">
+                <sources entry="main"><inline>void main() { gl_Position = ftransform(); }</inline></sources>
+              </shader>
+              <shader stage="FRAGMENT">
+                <sources entry="main">
+                  <inline>uniform float bands;
+</inline>
+                  <import ref="lighting"/>
+                  <inline><![CDATA[void main() { if (bands < 1.0) discard; }]]></inline>
+                </sources>
+              </shader>
+              <bind_attribute symbol="position"><semantic>POSITION</semantic></bind_attribute>
+              <bind_uniform symbol="bands"><param ref="bands"/></bind_uniform>
+              <bind_uniform symbol="edge"><float>0.5</float></bind_uniform>
+            </program>
+          </pass>
+          <pass sid="broken">
+            <program>
+              <shader stage="FRAGMENT"><sources><import ref="noise"/></sources></shader>
+            </program>
+          </pass>
+        </technique>
+      </profile_GLSL>
+    </effect>
+  </library_effects>
+</COLLADA>`
+
+func TestProfileGlsl(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(glslDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(collada *Collada) {
+		profile := collada.FindEffect("toon-fx").ProfileGlsl
+		if profile == nil || profile.Platform != "PC" || len(profile.TechniqueFx) != 1 {
+			t.Fatal("profile_GLSL not decoded")
+		}
+		passes := profile.TechniqueFx[0].Pass
+		if len(passes) != 2 || passes[0].States == nil || !strings.Contains(passes[0].States.XML, `<dest value="ONE_MINUS_SRC_ALPHA"`) {
+			t.Fatal("wrong passes")
+		}
+		sources, err := profile.PassSources(passes[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		fragment := "uniform float bands;\nfloat lit(vec3 n, vec3 l) { return max(dot(n, l), 0.0); }\nvoid main() { if (bands < 1.0) discard; }"
+		if sources[StageFragment] != fragment || sources[StageVertex] != "void main() { gl_Position = ftransform(); }" {
+			t.Error("wrong shader sources", sources)
+		}
+		program := passes[0].Program
+		if program.BindAttribute[0].Semantic != "POSITION" || program.BindUniform[0].Param.Ref != "bands" || *program.BindUniform[1].Float != 0.5 {
+			t.Error("wrong program bindings")
+		}
+		if _, err := profile.PassSources(passes[1]); err == nil {
+			t.Error("external include read")
+		}
+	}
+	check(collada)
+
+	buf := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadDocumentFromReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(reloaded)
+}
+
+const glesDocument = `<?xml version="1.0" encoding="utf-8"?>
+<COLLADA xmlns="http://www.collada.org/2008/03/COLLADASchema" version="1.5.0">
+  <library_effects>
+    <effect id="lightmap-fx">
+      <profile_GLES platforms="PC">
+        <newparam sid="detail">
+          <texture_pipeline>
+            <texenv operator="MODULATE" sampler="base-sampler"/>
+            <texcombiner>
+              <constant value="1 1 1 0.5"/>
+              <RGB operator="INTERPOLATE" scale="2">
+                <argument source="TEXTURE" operand="SRC_COLOR" sampler="light-sampler"/>
+                <argument source="PREVIOUS" operand="SRC_COLOR"/>
+                <argument source="CONSTANT" operand="SRC_ALPHA"/>
+              </RGB>
+              <alpha operator="REPLACE"><argument source="PREVIOUS" operand="SRC_ALPHA"/></alpha>
+            </texcombiner>
+          </texture_pipeline>
+        </newparam>
+        <technique sid="default">
+          <pass>
+            <states>
+              <texture_pipeline><texenv operator="REPLACE" sampler="base-sampler"/></texture_pipeline>
+            </states>
+          </pass>
+        </technique>
+      </profile_GLES>
+      <profile_GLES2 language="GLSLES" platforms="PC">
+        <code sid="vertex">void main() { gl_Position = vec4(0.0); }</code>
+        <technique sid="default">
+          <pass>
+            <program>
+              <shader stage="VERTEX">
+                <sources entry="main"><import ref="vertex"/></sources>
+                <compiler platform="GLES2" options="-O2"><binary><ref>vertex.bin</ref></binary></compiler>
+              </shader>
+              <linker platform="GLES2"/>
+            </program>
+          </pass>
+        </technique>
+      </profile_GLES2>
+    </effect>
+  </library_effects>
+</COLLADA>`
+
+func TestProfileGles(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(glesDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(collada *Collada) {
+		effect := collada.FindEffect("lightmap-fx")
+		if effect.ProfileGles == nil || effect.ProfileGles2 == nil {
+			t.Fatal("GLES profiles not decoded")
+		}
+		pipeline := effect.ProfileGles.Newparam[0].TexturePipeline
+		if pipeline == nil || len(pipeline.Stage) != 2 || pipeline.Stage[0].Texenv == nil || pipeline.Stage[1].Texcombiner == nil {
+			t.Fatal("wrong texture pipeline")
+		}
+		combiner := pipeline.Stage[1].Texcombiner
+		if len(combiner.Rgb.Argument) != 3 || *combiner.Rgb.Scale != 2 || combiner.Alpha.Operator != "REPLACE" || combiner.Constant.Value[3] != 0.5 {
+			t.Error("wrong texture combiner", combiner.Rgb, combiner.Alpha)
+		}
+		state, err := effect.ProfileGles.TechniqueFx[0].Pass[0].States.TexturePipeline()
+		if err != nil || state == nil || state.Stage[0].Texenv.Operator != "REPLACE" {
+			t.Error("wrong texture pipeline state", err)
+		}
+		profile := effect.ProfileGles2
+		pass := profile.TechniqueFx[0].Pass[0]
+		sources, err := profile.PassSources(pass)
+		if err != nil || sources[StageVertex] != "void main() { gl_Position = vec4(0.0); }" {
+			t.Error("wrong GLES2 sources", sources, err)
+		}
+		compiler := pass.Program.Shader[0].Compiler[0]
+		if compiler.Options != "-O2" || compiler.Binary.Ref != "vertex.bin" || len(pass.Program.Linker) != 1 {
+			t.Error("wrong GLES2 program")
+		}
+	}
+	check(collada)
+
+	buf := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadDocumentFromReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(reloaded)
+}
+
+const cgDocument = `<?xml version="1.0" encoding="utf-8"?>
+<COLLADA xmlns="http://www.collada.org/2008/03/COLLADASchema" version="1.5.0">
+  <library_effects>
+    <effect id="metal-fx">
+      <profile_BRIDGE platform="PC" url="effects/metal.cgfx"/>
+      <profile_CG platform="PS3">
+        <code sid="main">float4 main(float4 c : COLOR) : COLOR { return c * tint; }</code>
+        <newparam sid="tint"><float4>1 0.8 0.6 1</float4></newparam>
+        <technique sid="default">
+          <pass>
+            <program>
+              <shader stage="FRAGMENT">
+                <sources entry="main"><import ref="main"/></sources>
+                <compiler platform="PS3" target="fp40" options="-fastmath"/>
+                <bind_uniform symbol="tint"><param ref="tint"/></bind_uniform>
+              </shader>
+            </program>
+          </pass>
+        </technique>
+      </profile_CG>
+    </effect>
+  </library_effects>
+  <library_materials>
+    <material id="metal"><instance_effect url="#metal-fx"/></material>
+  </library_materials>
+</COLLADA>`
+
+func TestProfileCg(t *testing.T) {
+	collada, err := LoadDocumentFromReader(strings.NewReader(cgDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	effect := collada.FindEffect("metal-fx")
+	profile := effect.ProfileCg
+	if profile == nil || profile.Platform != "PS3" {
+		t.Fatal("profile_CG not decoded")
+	}
+	technique := profile.TechniqueFx[0]
+	shader := technique.Pass[0].Program.Shader[0]
+	if source, err := profile.ShaderSource(shader); err != nil || !strings.HasPrefix(source, "float4 main") {
+		t.Error("wrong CG source", source, err)
+	}
+	if shader.Compiler[0].Target != "fp40" || shader.BindUniform[0].Param.Ref != "tint" {
+		t.Error("wrong CG shader")
+	}
+	scope, err := collada.ParamScope(collada.FindMaterial("metal"), technique)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := scope.Param(shader.BindUniform[0].Param.Ref); err != nil || value.Float4 == nil {
+		t.Error("profile parameter not resolved", err)
+	}
+
+	path, err := collada.BridgePath(effect.ProfileBridge, filepath.Join("scenes", "metal.dae"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := filepath.Abs(filepath.Join("scenes", "effects", "metal.cgfx")); path != want {
+		t.Error("wrong bridge path", path)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := collada.ExportToWriter(buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`<profile_BRIDGE platform="PC" url="effects/metal.cgfx">`, `<compiler platform="PS3" target="fp40" options="-fastmath">`} {
+		if !strings.Contains(buf.String(), s) {
+			t.Error("not exported", s)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package collada
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+)
+
+//ExportStl writes the triangles of a visual scene to filename as STL.
+func (collada *Collada) ExportStl(filename string, options MeshExportOptions) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return collada.ExportStlToWriter(file, options)
+}
+
+//ExportStlToWriter writes the triangles of a visual scene as STL, baked into world space.
+//Polygons are split into fans and each facet normal is computed from the winding of its triangle.
+func (collada *Collada) ExportStlToWriter(writer io.Writer, options MeshExportOptions) error {
+	mesh, err := collada.bake(&options)
+	if err != nil {
+		return err
+	}
+	scene, _ := options.scene(collada)
+	name := objName(scene.Name)
+	if name == "" {
+		name = string(scene.Id)
+	}
+	w := bufio.NewWriter(writer)
+	if options.Binary {
+		//The 80 byte header must not begin with "solid", which marks an ASCII file.
+		header := make([]byte, 80)
+		copy(header, "binary STL "+name)
+		w.Write(header)
+		count := 0
+		mesh.triangles(func(a, b, c int) { count++ })
+		binary.Write(w, binary.LittleEndian, uint32(count))
+		record := make([]byte, 50)
+		mesh.triangles(func(a, b, c int) {
+			p := [3][3]float64{mesh.vertices[a].position, mesh.vertices[b].position, mesh.vertices[c].position}
+			n := faceNormal(p[0], p[1], p[2])
+			for i, v := range [][3]float64{n, p[0], p[1], p[2]} {
+				for j, f := range v {
+					binary.LittleEndian.PutUint32(record[(i*3+j)*4:], math.Float32bits(float32(f)))
+				}
+			}
+			w.Write(record)
+		})
+		return w.Flush()
+	}
+	writeVector := func(prefix string, v [3]float64) {
+		w.WriteString(prefix)
+		for _, f := range v {
+			w.WriteString(" ")
+			w.WriteString(formatFloat(f))
+		}
+		w.WriteString("\n")
+	}
+	w.WriteString("solid " + name + "\n")
+	mesh.triangles(func(a, b, c int) {
+		p := [3][3]float64{mesh.vertices[a].position, mesh.vertices[b].position, mesh.vertices[c].position}
+		writeVector("facet normal", faceNormal(p[0], p[1], p[2]))
+		w.WriteString("outer loop\n")
+		for _, v := range p {
+			writeVector("vertex", v)
+		}
+		w.WriteString("endloop\nendfacet\n")
+	})
+	w.WriteString("endsolid " + name + "\n")
+	return w.Flush()
+}
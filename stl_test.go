@@ -0,0 +1,77 @@
+package collada
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+//centimeterTriangle builds a document measured in centimeters with a translated triangle.
+func centimeterTriangle(t *testing.T) *Document {
+	doc := NewDocument()
+	doc.Collada.Asset.Unit = &Unit{HasName: HasName{"centimeter"}, Meter: 0.01}
+	geometry, err := doc.AddGeometry("Triangle", []float64{0, 0, 0, 1, 0, 0, 0, 1, 0}, nil, nil, []int{0, 1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := doc.AddNode("Triangle", nil)
+	node.Translate = append(node.Translate, NewTranslate(0, 0, 2))
+	doc.Instance(node, geometry, nil)
+	return doc
+}
+
+func TestExportStl(t *testing.T) {
+	collada := centimeterTriangle(t).Collada
+	buffer := &bytes.Buffer{}
+	if err := collada.ExportStlToWriter(buffer, MeshExportOptions{Binary: true, Meter: 0.001}); err != nil {
+		t.Fatal(err)
+	}
+	data := buffer.Bytes()
+	if len(data) != 84+50 || binary.LittleEndian.Uint32(data[80:]) != 1 {
+		t.Fatal("wrong binary stl size", len(data))
+	}
+	record := make([]float32, 12)
+	binary.Read(bytes.NewReader(data[84:]), binary.LittleEndian, record)
+	if record[2] != 1 || record[5] != 20 || record[6] != 10 {
+		t.Error("wrong facet", record)
+	}
+
+	buffer.Reset()
+	if err := collada.ExportStlToWriter(buffer, MeshExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	text := buffer.String()
+	if !strings.HasPrefix(text, "solid Scene\n") || strings.Count(text, "vertex ") != 3 || !strings.Contains(text, "vertex 1 0 2\n") {
+		t.Error("wrong ascii stl", text)
+	}
+
+	cube, err := LoadDocument("cube.dae")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer.Reset()
+	if err := cube.ExportStlToWriter(buffer, MeshExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if facets := strings.Count(buffer.String(), "facet normal"); facets != 12 {
+		t.Error("wrong number of facets", facets)
+	}
+	for _, line := range strings.Split(buffer.String(), "\n") {
+		if strings.HasPrefix(line, "facet normal") {
+			n := parseFloatsOrFail(t, strings.Fields(line)[2:])
+			if math.Abs(n[0]*n[0]+n[1]*n[1]+n[2]*n[2]-1) > 1e-9 {
+				t.Fatal("facet normal is not unit length", line)
+			}
+		}
+	}
+}
+
+func parseFloatsOrFail(t *testing.T, fields []string) []float64 {
+	vs, err := parseFloats(fields, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vs
+}
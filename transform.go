@@ -0,0 +1,324 @@
+package collada
+
+import (
+	"encoding/xml"
+	"math"
+)
+
+//Mat4 is a 4x4 transformation matrix stored in row-major order, as in the <matrix> element.
+//Points are column vectors, so m.Mul(n) applies n before m.
+type Mat4 [16]float64
+
+//Identity returns the identity matrix.
+func Identity() Mat4 {
+	return Mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+//Translation returns a matrix translating by (x, y, z).
+func Translation(x, y, z float64) Mat4 {
+	return Mat4{
+		1, 0, 0, x,
+		0, 1, 0, y,
+		0, 0, 1, z,
+		0, 0, 0, 1,
+	}
+}
+
+//Scaling returns a matrix scaling by (x, y, z).
+func Scaling(x, y, z float64) Mat4 {
+	return Mat4{
+		x, 0, 0, 0,
+		0, y, 0, 0,
+		0, 0, z, 0,
+		0, 0, 0, 1,
+	}
+}
+
+//Rotation returns a matrix rotating by angle degrees around the axis (x, y, z).
+func Rotation(x, y, z, angle float64) Mat4 {
+	l := math.Sqrt(x*x + y*y + z*z)
+	if l == 0 {
+		return Identity()
+	}
+	x, y, z = x/l, y/l, z/l
+	s, c := math.Sincos(angle * math.Pi / 180)
+	t := 1 - c
+	return Mat4{
+		t*x*x + c, t*x*y - s*z, t*x*z + s*y, 0,
+		t*x*y + s*z, t*y*y + c, t*y*z - s*x, 0,
+		t*x*z - s*y, t*y*z + s*x, t*z*z + c, 0,
+		0, 0, 0, 1,
+	}
+}
+
+//Mul returns the product m * n.
+func (m Mat4) Mul(n Mat4) Mat4 {
+	var r Mat4
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			var sum float64
+			for k := 0; k < 4; k++ {
+				sum += m[i*4+k] * n[k*4+j]
+			}
+			r[i*4+j] = sum
+		}
+	}
+	return r
+}
+
+//Transpose returns the transpose of m.
+func (m Mat4) Transpose() Mat4 {
+	var r Mat4
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			r[j*4+i] = m[i*4+j]
+		}
+	}
+	return r
+}
+
+//Inverse returns the inverse of m, or false if m is singular.
+func (m Mat4) Inverse() (Mat4, bool) {
+	var inv Mat4
+	inv[0] = m[5]*m[10]*m[15] - m[5]*m[11]*m[14] - m[9]*m[6]*m[15] + m[9]*m[7]*m[14] + m[13]*m[6]*m[11] - m[13]*m[7]*m[10]
+	inv[4] = -m[4]*m[10]*m[15] + m[4]*m[11]*m[14] + m[8]*m[6]*m[15] - m[8]*m[7]*m[14] - m[12]*m[6]*m[11] + m[12]*m[7]*m[10]
+	inv[8] = m[4]*m[9]*m[15] - m[4]*m[11]*m[13] - m[8]*m[5]*m[15] + m[8]*m[7]*m[13] + m[12]*m[5]*m[11] - m[12]*m[7]*m[9]
+	inv[12] = -m[4]*m[9]*m[14] + m[4]*m[10]*m[13] + m[8]*m[5]*m[14] - m[8]*m[6]*m[13] - m[12]*m[5]*m[10] + m[12]*m[6]*m[9]
+	inv[1] = -m[1]*m[10]*m[15] + m[1]*m[11]*m[14] + m[9]*m[2]*m[15] - m[9]*m[3]*m[14] - m[13]*m[2]*m[11] + m[13]*m[3]*m[10]
+	inv[5] = m[0]*m[10]*m[15] - m[0]*m[11]*m[14] - m[8]*m[2]*m[15] + m[8]*m[3]*m[14] + m[12]*m[2]*m[11] - m[12]*m[3]*m[10]
+	inv[9] = -m[0]*m[9]*m[15] + m[0]*m[11]*m[13] + m[8]*m[1]*m[15] - m[8]*m[3]*m[13] - m[12]*m[1]*m[11] + m[12]*m[3]*m[9]
+	inv[13] = m[0]*m[9]*m[14] - m[0]*m[10]*m[13] - m[8]*m[1]*m[14] + m[8]*m[2]*m[13] + m[12]*m[1]*m[10] - m[12]*m[2]*m[9]
+	inv[2] = m[1]*m[6]*m[15] - m[1]*m[7]*m[14] - m[5]*m[2]*m[15] + m[5]*m[3]*m[14] + m[13]*m[2]*m[7] - m[13]*m[3]*m[6]
+	inv[6] = -m[0]*m[6]*m[15] + m[0]*m[7]*m[14] + m[4]*m[2]*m[15] - m[4]*m[3]*m[14] - m[12]*m[2]*m[7] + m[12]*m[3]*m[6]
+	inv[10] = m[0]*m[5]*m[15] - m[0]*m[7]*m[13] - m[4]*m[1]*m[15] + m[4]*m[3]*m[13] + m[12]*m[1]*m[7] - m[12]*m[3]*m[5]
+	inv[14] = -m[0]*m[5]*m[14] + m[0]*m[6]*m[13] + m[4]*m[1]*m[14] - m[4]*m[2]*m[13] - m[12]*m[1]*m[6] + m[12]*m[2]*m[5]
+	inv[3] = -m[1]*m[6]*m[11] + m[1]*m[7]*m[10] + m[5]*m[2]*m[11] - m[5]*m[3]*m[10] - m[9]*m[2]*m[7] + m[9]*m[3]*m[6]
+	inv[7] = m[0]*m[6]*m[11] - m[0]*m[7]*m[10] - m[4]*m[2]*m[11] + m[4]*m[3]*m[10] + m[8]*m[2]*m[7] - m[8]*m[3]*m[6]
+	inv[11] = -m[0]*m[5]*m[11] + m[0]*m[7]*m[9] + m[4]*m[1]*m[11] - m[4]*m[3]*m[9] - m[8]*m[1]*m[7] + m[8]*m[3]*m[5]
+	inv[15] = m[0]*m[5]*m[10] - m[0]*m[6]*m[9] - m[4]*m[1]*m[10] + m[4]*m[2]*m[9] + m[8]*m[1]*m[6] - m[8]*m[2]*m[5]
+	det := m[0]*inv[0] + m[1]*inv[4] + m[2]*inv[8] + m[3]*inv[12]
+	if det == 0 {
+		return Mat4{}, false
+	}
+	for i := range inv {
+		inv[i] /= det
+	}
+	return inv, true
+}
+
+//NormalMatrix returns the matrix which transforms normals consistently with m.
+func (m Mat4) NormalMatrix() Mat4 {
+	inv, ok := m.Inverse()
+	if !ok {
+		return m
+	}
+	return inv.Transpose()
+}
+
+//TransformPoint applies m to the point (x, y, z).
+func (m Mat4) TransformPoint(x, y, z float64) (float64, float64, float64) {
+	w := m[12]*x + m[13]*y + m[14]*z + m[15]
+	if w == 0 {
+		w = 1
+	}
+	return (m[0]*x + m[1]*y + m[2]*z + m[3]) / w,
+		(m[4]*x + m[5]*y + m[6]*z + m[7]) / w,
+		(m[8]*x + m[9]*y + m[10]*z + m[11]) / w
+}
+
+//TransformVector applies the linear part of m to the direction (x, y, z).
+func (m Mat4) TransformVector(x, y, z float64) (float64, float64, float64) {
+	return m[0]*x + m[1]*y + m[2]*z,
+		m[4]*x + m[5]*y + m[6]*z,
+		m[8]*x + m[9]*y + m[10]*z
+}
+
+func normalize(x, y, z float64) (float64, float64, float64) {
+	l := math.Sqrt(x*x + y*y + z*z)
+	if l == 0 {
+		return x, y, z
+	}
+	return x / l, y / l, z / l
+}
+
+func cross(ax, ay, az, bx, by, bz float64) (float64, float64, float64) {
+	return ay*bz - az*by, az*bx - ax*bz, ax*by - ay*bx
+}
+
+//floatsAt returns the value at index i, or 0 if the array is too short.
+func floatsAt(vs []float64, i int) float64 {
+	if i < len(vs) {
+		return vs[i]
+	}
+	return 0
+}
+
+//Mat4 returns the transform of a <lookat> element, which positions an object at the eye looking at the interest point.
+func (lookat *Lookat) Mat4() Mat4 {
+	v := lookat.F()
+	ex, ey, ez := floatsAt(v, 0), floatsAt(v, 1), floatsAt(v, 2)
+	fx, fy, fz := normalize(floatsAt(v, 3)-ex, floatsAt(v, 4)-ey, floatsAt(v, 5)-ez)
+	sx, sy, sz := normalize(cross(fx, fy, fz, floatsAt(v, 6), floatsAt(v, 7), floatsAt(v, 8)))
+	ux, uy, uz := cross(sx, sy, sz, fx, fy, fz)
+	return Mat4{
+		sx, ux, -fx, ex,
+		sy, uy, -fy, ey,
+		sz, uz, -fz, ez,
+		0, 0, 0, 1,
+	}
+}
+
+//Mat4 returns the transform of a <matrix> element.
+func (matrix *Matrix) Mat4() Mat4 {
+	var m Mat4
+	copy(m[:], matrix.F())
+	return m
+}
+
+//Mat4 returns the transform of a <translate> element.
+func (translate *Translate) Mat4() Mat4 {
+	v := translate.F()
+	return Translation(floatsAt(v, 0), floatsAt(v, 1), floatsAt(v, 2))
+}
+
+//Mat4 returns the transform of a <rotate> element.
+func (rotate *Rotate) Mat4() Mat4 {
+	v := rotate.F()
+	return Rotation(floatsAt(v, 0), floatsAt(v, 1), floatsAt(v, 2), floatsAt(v, 3))
+}
+
+//Mat4 returns the transform of a <scale> element.
+func (scale *Scale) Mat4() Mat4 {
+	v := scale.F()
+	return Scaling(floatsAt(v, 0), floatsAt(v, 1), floatsAt(v, 2))
+}
+
+//Mat4 returns the transform of a <skew> element, which holds an angle in degrees,
+//the axis of rotation and the axis of translation.
+func (skew *Skew) Mat4() Mat4 {
+	v := skew.F()
+	ax, ay, az := normalize(floatsAt(v, 1), floatsAt(v, 2), floatsAt(v, 3))
+	bx, by, bz := normalize(floatsAt(v, 4), floatsAt(v, 5), floatsAt(v, 6))
+	s := math.Tan(floatsAt(v, 0) * math.Pi / 180)
+	a := [3]float64{ax, ay, az}
+	b := [3]float64{bx, by, bz}
+	m := Identity()
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			m[i*4+j] += s * b[i] * a[j]
+		}
+	}
+	return m
+}
+
+//Mat4 returns the transform of a <translate> or <rotate>, or the identity for an element of another kind.
+func (transform *Transform) Mat4() Mat4 {
+	switch {
+	case transform.Translate != nil:
+		return transform.Translate.Mat4()
+	case transform.Rotate != nil:
+		return transform.Rotate.Mat4()
+	}
+	return Identity()
+}
+
+//Mat4 composes the transforms in document order.
+func (transforms Transforms) Mat4() Mat4 {
+	m := Identity()
+	for _, t := range transforms {
+		m = m.Mul(t.Mat4())
+	}
+	return m
+}
+
+//UnmarshalXML decodes a <translate> or <rotate>, skipping elements of other kinds.
+func (transform *Transform) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return transform.unmarshalXML(d, start, d.DecodeElement)
+}
+
+//unmarshalXML decodes the children through decode.
+func (transform *Transform) unmarshalXML(d *xml.Decoder, start xml.StartElement, decode func(v interface{}, start *xml.StartElement) error) error {
+	*transform = Transform{}
+	switch start.Name.Local {
+	case "translate":
+		transform.Translate = &Translate{}
+		return decode(transform.Translate, &start)
+	case "rotate":
+		transform.Rotate = &Rotate{}
+		return decode(transform.Rotate, &start)
+	}
+	return d.Skip()
+}
+
+//MarshalXML encodes the transform as a <translate> or <rotate>.
+func (transform Transform) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return transform.marshalXML(e, start, e.EncodeElement)
+}
+
+//marshalXML encodes the children through encode.
+func (transform Transform) marshalXML(e *xml.Encoder, start xml.StartElement, encode func(v interface{}, start xml.StartElement) error) error {
+	switch {
+	case transform.Translate != nil:
+		return encode(transform.Translate, xml.StartElement{Name: xml.Name{Local: "translate"}})
+	case transform.Rotate != nil:
+		return encode(transform.Rotate, xml.StartElement{Name: xml.Name{Local: "rotate"}})
+	}
+	return nil
+}
+
+//LocalTransform returns the transform of a node relative to its parent.
+//The transform elements are applied in the order of the Node struct fields, since the order in the
+//document is not preserved by the decoder (see README).
+func (node *Node) LocalTransform() Mat4 {
+	m := Identity()
+	for _, t := range node.Lookat {
+		m = m.Mul(t.Mat4())
+	}
+	for _, t := range node.Matrix {
+		m = m.Mul(t.Mat4())
+	}
+	for _, t := range node.Translate {
+		m = m.Mul(t.Mat4())
+	}
+	for _, t := range node.Rotate {
+		m = m.Mul(t.Mat4())
+	}
+	for _, t := range node.Scale {
+		m = m.Mul(t.Mat4())
+	}
+	for _, t := range node.Skew {
+		m = m.Mul(t.Mat4())
+	}
+	return m
+}
+
+//VisitNodes calls visit for every node of a visual scene with its world transform, parents before children.
+//Nodes referenced through <instance_node> are visited beneath the instancing node.
+func (collada *Collada) VisitNodes(scene *VisualScene, visit func(node *Node, world Mat4)) {
+	var walk func(nodes []*Node, parent Mat4, path map[*Node]bool)
+	walk = func(nodes []*Node, parent Mat4, path map[*Node]bool) {
+		for _, node := range nodes {
+			if path[node] {
+				continue
+			}
+			world := parent.Mul(node.LocalTransform())
+			visit(node, world)
+			path[node] = true
+			walk(node.Node, world, path)
+			for _, instance := range node.InstanceNode {
+				if id, ok := instance.Url.Id(); ok {
+					if instanced := collada.FindNode(id); instanced != nil {
+						walk([]*Node{instanced}, world, path)
+					}
+				}
+			}
+			delete(path, node)
+		}
+	}
+	walk(scene.Node, Identity(), make(map[*Node]bool))
+}
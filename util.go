@@ -2,8 +2,6 @@ package collada
 
 import (
 	"regexp"
-	"strconv"
-	"strings"
 )
 
 func (uri *Uri) Id() (Id, bool) {
@@ -19,35 +17,250 @@ func (node *Node) HasGeometry() bool {
 	return len(node.InstanceGeometry) > 0
 }
 
-func (values *Values) Components() []string {
-    return strings.Split(values.V, " ")
-}
-
+//I returns the values as ints.
 func (ints *Ints) I() []int {
-	ss := ints.Values.Components()
-	vs := make([]int, len(ss))
-	for i, value := range ss {
-		vs[i], _ = strconv.Atoi(value)
+	vs := make([]int, len(ints.V))
+	for i, value := range ints.V {
+		vs[i] = int(value)
 	}
 	return vs
 }
 
+//F returns the values as float64s.
 func (floats *Floats) F() []float64 {
-	ss := floats.Components()
-	vs := make([]float64, len(ss))
-	for i, value := range ss {
-		vs[i], _ = strconv.ParseFloat(value, 64)
-	}
-	return vs
+	return floats.V
 }
 
 func (floats *Floats) F32() []float32 {
-	ss := floats.Components()
-	vs := make([]float32, len(ss))
-	for i, value := range ss {
-		f, _ := strconv.ParseFloat(value, 32)
-        vs[i] = float32(f)
+	vs := make([]float32, len(floats.V))
+	for i, value := range floats.V {
+		vs[i] = float32(value)
 	}
 	return vs
 }
 
+//FindGeometry returns the geometry with the given id, or nil if there is none.
+func (collada *Collada) FindGeometry(id Id) *Geometry {
+	for _, library := range collada.LibraryGeometries {
+		for _, geometry := range library.Geometry {
+			if geometry.Id == id {
+				return geometry
+			}
+		}
+	}
+	return nil
+}
+
+//FindMaterial returns the material with the given id, or nil if there is none.
+func (collada *Collada) FindMaterial(id Id) *Material {
+	for _, library := range collada.LibraryMaterials {
+		for _, material := range library.Material {
+			if material.Id == id {
+				return material
+			}
+		}
+	}
+	return nil
+}
+
+//FindImage returns the image with the given id, or nil if there is none.
+func (collada *Collada) FindImage(id Id) *Image {
+	for _, library := range collada.LibraryImages {
+		for _, image := range library.Image {
+			if image.Id == id {
+				return image
+			}
+		}
+	}
+	return nil
+}
+
+//FindEffect returns the effect with the given id, or nil if there is none.
+func (collada *Collada) FindEffect(id Id) *Effect {
+	for _, library := range collada.LibraryEffects {
+		for _, effect := range library.Effect {
+			if effect.Id == id {
+				return effect
+			}
+		}
+	}
+	return nil
+}
+
+//FindCamera returns the camera with the given id, or nil if there is none.
+func (collada *Collada) FindCamera(id Id) *Camera {
+	for _, library := range collada.LibraryCameras {
+		for _, camera := range library.Camera {
+			if camera.Id == id {
+				return camera
+			}
+		}
+	}
+	return nil
+}
+
+//FindLight returns the light with the given id, or nil if there is none.
+func (collada *Collada) FindLight(id Id) *Light {
+	for _, library := range collada.LibraryLights {
+		for _, light := range library.Light {
+			if light.Id == id {
+				return light
+			}
+		}
+	}
+	return nil
+}
+
+//FindJoint returns the joint with the given id, or nil if there is none.
+func (collada *Collada) FindJoint(id Id) *Joint {
+	for _, library := range collada.LibraryJoints {
+		for _, joint := range library.Joint {
+			if joint.Id == id {
+				return joint
+			}
+		}
+	}
+	return nil
+}
+
+//FindKinematicsModel returns the kinematics model with the given id, or nil if there is none.
+func (collada *Collada) FindKinematicsModel(id Id) *KinematicsModel {
+	for _, library := range collada.LibraryKinematicsModels {
+		for _, kinematicsModel := range library.KinematicsModel {
+			if kinematicsModel.Id == id {
+				return kinematicsModel
+			}
+		}
+	}
+	return nil
+}
+
+//FindArticulatedSystem returns the articulated system with the given id, or nil if there is none.
+func (collada *Collada) FindArticulatedSystem(id Id) *ArticulatedSystem {
+	for _, library := range collada.LibraryArticulatedSystems {
+		for _, articulatedSystem := range library.ArticulatedSystem {
+			if articulatedSystem.Id == id {
+				return articulatedSystem
+			}
+		}
+	}
+	return nil
+}
+
+//FindKinematicsScene returns the kinematics scene with the given id, or nil if there is none.
+func (collada *Collada) FindKinematicsScene(id Id) *KinematicsScene {
+	for _, library := range collada.LibraryKinematicsScenes {
+		for _, kinematicsScene := range library.KinematicsScene {
+			if kinematicsScene.Id == id {
+				return kinematicsScene
+			}
+		}
+	}
+	return nil
+}
+
+//FindForceField returns the force field with the given id, or nil if there is none.
+func (collada *Collada) FindForceField(id Id) *ForceField {
+	for _, library := range collada.LibraryForceFields {
+		for _, forceField := range library.ForceField {
+			if forceField.Id == id {
+				return forceField
+			}
+		}
+	}
+	return nil
+}
+
+//FindPhysicsMaterial returns the physics material with the given id, or nil if there is none.
+func (collada *Collada) FindPhysicsMaterial(id Id) *PhysicsMaterial {
+	for _, library := range collada.LibraryPhysicsMaterials {
+		for _, material := range library.PhysicsMaterial {
+			if material.Id == id {
+				return material
+			}
+		}
+	}
+	return nil
+}
+
+//FindPhysicsModel returns the physics model with the given id, or nil if there is none.
+func (collada *Collada) FindPhysicsModel(id Id) *PhysicsModel {
+	for _, library := range collada.LibraryPhysicsModels {
+		for _, model := range library.PhysicsModel {
+			if model.Id == id {
+				return model
+			}
+		}
+	}
+	return nil
+}
+
+//FindPhysicsScene returns the physics scene with the given id, or nil if there is none.
+func (collada *Collada) FindPhysicsScene(id Id) *PhysicsScene {
+	for _, library := range collada.LibraryPhysicsScenes {
+		for _, physicsScene := range library.PhysicsScene {
+			if physicsScene.Id == id {
+				return physicsScene
+			}
+		}
+	}
+	return nil
+}
+
+//FindVisualScene returns the visual scene with the given id, or nil if there is none.
+func (collada *Collada) FindVisualScene(id Id) *VisualScene {
+	for _, library := range collada.LibraryVisualScenes {
+		for _, scene := range library.VisualScene {
+			if scene.Id == id {
+				return scene
+			}
+		}
+	}
+	return nil
+}
+
+//FindNode returns the node with the given id from the node or visual scene libraries, or nil if there is none.
+func (collada *Collada) FindNode(id Id) *Node {
+	for _, library := range collada.LibraryNodes {
+		if node := findNode(library.Node, id); node != nil {
+			return node
+		}
+	}
+	for _, library := range collada.LibraryVisualScenes {
+		for _, scene := range library.VisualScene {
+			if node := findNode(scene.Node, id); node != nil {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+func findNode(nodes []*Node, id Id) *Node {
+	for _, node := range nodes {
+		if node.Id == id {
+			return node
+		}
+		if found := findNode(node.Node, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+//DefaultVisualScene returns the visual scene instanced by <scene>, or the first visual scene if there is no instance.
+func (collada *Collada) DefaultVisualScene() *VisualScene {
+	if collada.Scene != nil && collada.Scene.InstanceVisualScene != nil {
+		if id, ok := collada.Scene.InstanceVisualScene.Url.Id(); ok {
+			if scene := collada.FindVisualScene(id); scene != nil {
+				return scene
+			}
+		}
+	}
+	for _, library := range collada.LibraryVisualScenes {
+		if len(library.VisualScene) > 0 {
+			return library.VisualScene[0]
+		}
+	}
+	return nil
+}
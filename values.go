@@ -0,0 +1,167 @@
+package collada
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+//FloatFormat controls how floating-point values are written on export.
+//The zero FloatFormat writes the shortest text that reads back to the same value, so a document that is
+//loaded and exported repeatedly stays byte-stable.
+type FloatFormat struct {
+	//Fmt and Prec are passed to strconv.AppendFloat.
+	Fmt  byte
+	Prec int
+	//CompactExponent writes exponents without a plus sign or leading zeros, as in 1e-7 rather than 1e-07.
+	CompactExponent bool
+}
+
+//FloatValues is a whitespace separated list of floating-point values.
+type FloatValues []float64
+
+//IntValues is a whitespace separated list of integer values.
+type IntValues []int32
+
+//BoolValues is a whitespace separated list of boolean values.
+type BoolValues []bool
+
+//StringValues is a whitespace separated list of names, tokens or references.
+type StringValues []string
+
+//HexBinary is binary data written as hexadecimal digits, which may be broken by whitespace.
+type HexBinary []byte
+
+func (format FloatFormat) append(dst []byte, f float64) []byte {
+	if format == (FloatFormat{}) {
+		format = FloatFormat{Fmt: 'g', Prec: -1, CompactExponent: true}
+	}
+	start := len(dst)
+	dst = strconv.AppendFloat(dst, f, format.Fmt, format.Prec, 64)
+	if !format.CompactExponent {
+		return dst
+	}
+	e := bytes.IndexAny(dst[start:], "eE")
+	if e < 0 {
+		return dst
+	}
+	e += start + 1
+	digits := e
+	if dst[digits] == '+' || dst[digits] == '-' {
+		digits++
+	}
+	end := digits
+	for end < len(dst)-1 && dst[end] == '0' {
+		end++
+	}
+	if dst[e] == '+' {
+		digits = e
+	}
+	return append(dst[:digits], dst[end:]...)
+}
+
+func (values FloatValues) MarshalText() ([]byte, error) {
+	text := make([]byte, 0, len(values)*8)
+	for i, f := range values {
+		if i > 0 {
+			text = append(text, ' ')
+		}
+		text = FloatFormat{}.append(text, f)
+	}
+	return text, nil
+}
+
+func (values *FloatValues) UnmarshalText(text []byte) error {
+	fields := bytes.Fields(text)
+	vs := make(FloatValues, len(fields))
+	for i, field := range fields {
+		f, err := strconv.ParseFloat(string(field), 64)
+		if err != nil {
+			return err
+		}
+		vs[i] = f
+	}
+	*values = vs
+	return nil
+}
+
+func (values IntValues) MarshalText() ([]byte, error) {
+	text := make([]byte, 0, len(values)*4)
+	for i, v := range values {
+		if i > 0 {
+			text = append(text, ' ')
+		}
+		text = strconv.AppendInt(text, int64(v), 10)
+	}
+	return text, nil
+}
+
+func (values *IntValues) UnmarshalText(text []byte) error {
+	fields := bytes.Fields(text)
+	vs := make(IntValues, len(fields))
+	for i, field := range fields {
+		v, err := strconv.ParseInt(string(field), 10, 32)
+		if err != nil {
+			return err
+		}
+		vs[i] = int32(v)
+	}
+	*values = vs
+	return nil
+}
+
+func (values BoolValues) MarshalText() ([]byte, error) {
+	text := make([]byte, 0, len(values)*5)
+	for i, v := range values {
+		if i > 0 {
+			text = append(text, ' ')
+		}
+		text = strconv.AppendBool(text, v)
+	}
+	return text, nil
+}
+
+func (values *BoolValues) UnmarshalText(text []byte) error {
+	fields := bytes.Fields(text)
+	vs := make(BoolValues, len(fields))
+	for i, field := range fields {
+		v, err := strconv.ParseBool(string(field))
+		if err != nil {
+			return err
+		}
+		vs[i] = v
+	}
+	*values = vs
+	return nil
+}
+
+func (values StringValues) MarshalText() ([]byte, error) {
+	return []byte(strings.Join(values, " ")), nil
+}
+
+func (values *StringValues) UnmarshalText(text []byte) error {
+	fields := bytes.Fields(text)
+	vs := make(StringValues, len(fields))
+	for i, field := range fields {
+		vs[i] = string(field)
+	}
+	*values = vs
+	return nil
+}
+
+func (data HexBinary) MarshalText() ([]byte, error) {
+	text := make([]byte, hex.EncodedLen(len(data)))
+	hex.Encode(text, data)
+	return bytes.ToUpper(text), nil
+}
+
+func (data *HexBinary) UnmarshalText(text []byte) error {
+	digits := bytes.Join(bytes.Fields(text), nil)
+	decoded := make(HexBinary, hex.DecodedLen(len(digits)))
+	if _, err := hex.Decode(decoded, digits); err != nil {
+		return err
+	}
+	*data = decoded
+	return nil
+}